@@ -0,0 +1,613 @@
+package extconnect
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/jamescun/http2/frames"
+	"github.com/jamescun/http2/hpack"
+	"github.com/jamescun/http2/settings"
+)
+
+var (
+	// ErrTooManyStreams is returned by NewStream when the server's limit on
+	// concurrent Streams has been reached.
+	ErrTooManyStreams = errors.New("extconnect: too many concurrent streams")
+
+	// ErrStreamsExhausted is returned by NewStream when every client Stream
+	// identifier has been used, a new connection must be established.
+	ErrStreamsExhausted = errors.New("extconnect: stream identifiers exhausted")
+
+	// ErrGoAway is returned by NewStream after the server has sent a GoAway
+	// frame, and by Streams the server did not process before sending it.
+	ErrGoAway = errors.New("extconnect: connection going away")
+)
+
+// Preface is sent by a client to begin an HTTP/2 connection, it is followed
+// by a Settings frame.
+// RFC 7540 Section 3.5
+const Preface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+const (
+	// initialWindowSize is the initial flow-control window of both the
+	// connection and new Streams.
+	// RFC 7540 Section 6.9.2
+	initialWindowSize = 65535
+
+	// windowUpdateThreshold is the number of bytes which must be read before
+	// they are returned to a flow-control window, avoiding a WindowUpdate
+	// frame for every Read.
+	windowUpdateThreshold = initialWindowSize / 2
+
+	// maxWindowSize is the largest flow-control window permitted.
+	// RFC 7540 Section 6.9.1
+	maxWindowSize = 1<<31 - 1
+
+	// maxStreamID is the largest Stream identifier permitted.
+	// RFC 7540 Section 5.1.1
+	maxStreamID = 1<<31 - 1
+)
+
+// Conn is the client side of an HTTP/2 connection, multiplexing any number of
+// Streams over a single underlying connection. Conn handles Settings, Ping and
+// flow control on behalf of its Streams.
+type Conn struct {
+	conn io.ReadWriteCloser
+	r    *frames.Reader
+	dec  *hpack.Decoder
+
+	// wmu guards w and enc, as header blocks MUST be written in the order
+	// they are encoded, and Streams MUST be opened in the order they are
+	// numbered. It is never acquired while holding mu.
+	wmu    sync.Mutex
+	w      *frames.Writer
+	enc    *hpack.Encoder
+	nextID uint32
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	err        error
+	goAway     bool
+	peer       settings.Values
+	streams    map[uint32]*Stream
+	lastID     uint32
+	sendWindow int64
+	recvWindow int64
+	released   int64
+}
+
+// NewConn begins a new HTTP/2 connection over conn, returning once the
+// server's Settings have been received.
+func NewConn(conn io.ReadWriteCloser) (*Conn, error) {
+	c := &Conn{
+		conn:       conn,
+		r:          frames.NewReader(conn),
+		dec:        hpack.NewDecoder(),
+		w:          frames.NewWriter(conn),
+		enc:        hpack.NewEncoder(),
+		nextID:     1,
+		peer:       settings.DefaultValues(),
+		streams:    make(map[uint32]*Stream),
+		sendWindow: initialWindowSize,
+		recvWindow: initialWindowSize,
+	}
+	c.cond = sync.NewCond(&c.mu)
+
+	if err := c.handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go c.readLoop()
+
+	return c, nil
+}
+
+// handshake sends the client connection preface and processes the Settings
+// frame which MUST begin the server connection preface.
+func (c *Conn) handshake() error {
+	if _, err := io.WriteString(c.conn, Preface); err != nil {
+		return err
+	}
+
+	err := c.writeFrames(&frames.Settings{Settings: []settings.Setting{
+		settings.EnablePush{Enabled: false},
+		c.dec.MaxHeaderListSize,
+	}})
+	if err != nil {
+		return err
+	}
+
+	frame, err := c.r.ReadFrame()
+	if err != nil {
+		return err
+	}
+
+	if f, ok := frame.(*frames.Settings); !ok || f.Ack {
+		return frames.ConnectionError{Code: frames.ErrorCodeProtocol, Reason: "extconnect: expected settings"}
+	}
+
+	return c.process(frame)
+}
+
+// Settings returns the current Settings of the server.
+func (c *Conn) Settings() settings.Values {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.peer
+}
+
+// NewStream opens a new Stream by sending fields as the header block of a
+// request, without ending the Stream.
+func (c *Conn) NewStream(fields []hpack.HeaderField) (*Stream, error) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	c.mu.Lock()
+	switch {
+	case c.err != nil:
+		err := c.err
+		c.mu.Unlock()
+		return nil, err
+
+	case c.goAway:
+		c.mu.Unlock()
+		return nil, ErrGoAway
+
+	case c.nextID > maxStreamID:
+		c.mu.Unlock()
+		return nil, ErrStreamsExhausted
+
+	case uint32(len(c.streams)) >= c.peer.MaxConcurrentStreams.Streams:
+		c.mu.Unlock()
+		return nil, ErrTooManyStreams
+	}
+
+	s := &Stream{
+		conn:       c,
+		id:         c.nextID,
+		sendWindow: int64(c.peer.InitialWindowSize.Size),
+		recvWindow: initialWindowSize,
+	}
+	c.streams[s.id] = s
+	c.lastID = s.id
+	limit := int(c.peer.MaxFrameSize.Size)
+	c.mu.Unlock()
+
+	c.nextID += 2
+
+	block := c.enc.AppendHeaderBlock(nil, fields)
+
+	if err := c.writeHeaders(s.id, block, limit); err != nil {
+		c.fail(err)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// writeHeaders writes block as a Headers frame followed by as many
+// Continuation frames as required by limit, the maximum frame size of the
+// server. It MUST be called while holding wmu.
+func (c *Conn) writeHeaders(id uint32, block []byte, limit int) error {
+	first := block
+	if len(first) > limit {
+		first = first[:limit]
+	}
+	block = block[len(first):]
+
+	err := c.w.WriteFrame(&frames.Headers{
+		Header:     frames.Header{StreamID: id},
+		EndHeaders: len(block) == 0,
+		Block:      first,
+	})
+	if err != nil {
+		return err
+	}
+
+	for len(block) > 0 {
+		next := block
+		if len(next) > limit {
+			next = next[:limit]
+		}
+		block = block[len(next):]
+
+		err = c.w.WriteFrame(&frames.Continuation{
+			Header:     frames.Header{StreamID: id},
+			EndHeaders: len(block) == 0,
+			Block:      next,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.w.Flush()
+}
+
+// writeFrames writes and flushes one or more Frames. Any error is fatal to
+// the connection, and is recorded on every Stream.
+func (c *Conn) writeFrames(f ...frames.Frame) error {
+	c.wmu.Lock()
+	err := c.writeFramesLocked(f...)
+	c.wmu.Unlock()
+
+	if err != nil {
+		c.fail(err)
+	}
+
+	return err
+}
+
+func (c *Conn) writeFramesLocked(f ...frames.Frame) error {
+	for _, frame := range f {
+		if err := c.w.WriteFrame(frame); err != nil {
+			return err
+		}
+	}
+
+	return c.w.Flush()
+}
+
+// readLoop processes Frames from the server until the connection fails.
+func (c *Conn) readLoop() {
+	var assembler frames.HeaderBlockAssembler
+
+	for {
+		frame, err := c.r.ReadFrame()
+		if err == nil {
+			frame, err = assembler.Assemble(frame)
+		}
+		if err == nil && frame != nil {
+			err = c.process(frame)
+		}
+
+		if err == nil || err == frames.ErrIgnoreFrame {
+			continue
+		}
+
+		var streamErr frames.StreamError
+		if errors.As(err, &streamErr) {
+			c.resetStream(streamErr)
+			continue
+		}
+
+		var connErr frames.ConnectionError
+		if errors.As(err, &connErr) {
+			c.writeFrames(&frames.GoAway{LastStreamID: 0, Code: connErr.Code, DebugData: []byte(connErr.Reason)})
+		}
+
+		c.fail(err)
+		c.conn.Close()
+
+		return
+	}
+}
+
+// fail records err as the cause of the connection ending, ending every
+// Stream and waking any blocked readers and writers.
+func (c *Conn) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err == nil {
+		c.err = err
+	}
+
+	for id, s := range c.streams {
+		s.fail(c.err)
+		delete(c.streams, id)
+	}
+
+	c.cond.Broadcast()
+}
+
+// resetStream ends the Stream of err, if it is open, and informs the server
+// with a ResetStream frame.
+func (c *Conn) resetStream(err frames.StreamError) {
+	c.mu.Lock()
+	if s, ok := c.streams[err.StreamID]; ok {
+		s.fail(err)
+		delete(c.streams, err.StreamID)
+		c.cond.Broadcast()
+	}
+	c.mu.Unlock()
+
+	c.writeFrames(&frames.ResetStream{Header: frames.Header{StreamID: err.StreamID}, Code: err.Code})
+}
+
+// process handles a single Frame from the server.
+func (c *Conn) process(frame frames.Frame) error {
+	switch f := frame.(type) {
+	case *frames.Settings:
+		if f.Ack {
+			return nil
+		}
+
+		changed, err := c.applySettings(f.Settings)
+		if err != nil {
+			return err
+		}
+
+		// NOTE(jc): the Encoder MUST adopt any new dynamic table size before
+		// the Settings are acknowledged and any further header block is
+		// encoded.
+		c.wmu.Lock()
+		for _, setting := range changed {
+			if size, ok := setting.(settings.HeaderTableSize); ok {
+				c.enc.SetHeaderTableSize(size)
+			}
+		}
+		err = c.writeFramesLocked(&frames.Settings{Ack: true})
+		c.wmu.Unlock()
+
+		if err != nil {
+			c.fail(err)
+		}
+
+		return err
+
+	case *frames.Ping:
+		if f.Ack {
+			return nil
+		}
+
+		return c.writeFrames(&frames.Ping{Ack: true, Data: f.Data})
+
+	case *frames.WindowUpdate:
+		return c.windowUpdate(f)
+
+	case *frames.Data:
+		return c.data(f)
+
+	case *frames.Headers:
+		return c.headers(f)
+
+	case *frames.PushPromise:
+		// NOTE(jc): push is disabled by the client connection preface.
+		return frames.ConnectionError{Code: frames.ErrorCodeProtocol, Reason: "extconnect: push promise received"}
+
+	case *frames.ResetStream:
+		c.mu.Lock()
+		if s, ok := c.streams[f.StreamID]; ok {
+			s.fail(frames.StreamError{StreamID: f.StreamID, Code: f.Code, Reason: "extconnect: reset by server"})
+			delete(c.streams, f.StreamID)
+			c.cond.Broadcast()
+		}
+		c.mu.Unlock()
+
+	case *frames.GoAway:
+		c.mu.Lock()
+		c.goAway = true
+		for id, s := range c.streams {
+			if id > f.LastStreamID {
+				s.fail(ErrGoAway)
+				delete(c.streams, id)
+			}
+		}
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+// applySettings applies the Settings of the server, adjusting the
+// flow-control window of every Stream by any change to its initial window
+// size, and returns the Settings which changed.
+// RFC 7540 Section 6.9.2
+func (c *Conn) applySettings(s []settings.Setting) ([]settings.Setting, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	enabled := c.peer.EnableConnectProtocol.Enabled
+
+	for _, setting := range s {
+		// NOTE(jc): once enabled, the Extended CONNECT method MUST NOT be
+		// disabled.
+		if ecp, ok := setting.(settings.EnableConnectProtocol); ok {
+			if enabled && !ecp.Enabled {
+				return nil, frames.ConnectionError{Code: frames.ErrorCodeProtocol, Reason: "extconnect: extended connect disabled"}
+			}
+
+			enabled = ecp.Enabled
+		}
+	}
+
+	prev := c.peer
+	c.peer.Apply(s)
+	delta := int64(c.peer.InitialWindowSize.Size) - int64(prev.InitialWindowSize.Size)
+
+	for _, stream := range c.streams {
+		stream.sendWindow += delta
+		if stream.sendWindow > maxWindowSize {
+			return nil, frames.ConnectionError{Code: frames.ErrorCodeFlowControl, Reason: "extconnect: window size overflow"}
+		}
+	}
+
+	c.cond.Broadcast()
+
+	return prev.Diff(c.peer), nil
+}
+
+// windowUpdate increases the flow-control window of the connection or a
+// Stream.
+// RFC 7540 Section 6.9.1
+func (c *Conn) windowUpdate(f *frames.WindowUpdate) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f.StreamID == 0 {
+		c.sendWindow += int64(f.Increment)
+		if c.sendWindow > maxWindowSize {
+			return frames.ConnectionError{Code: frames.ErrorCodeFlowControl, Reason: "extconnect: window size overflow"}
+		}
+	} else if s, ok := c.streams[f.StreamID]; ok {
+		s.sendWindow += int64(f.Increment)
+		if s.sendWindow > maxWindowSize {
+			return frames.StreamError{StreamID: f.StreamID, Code: frames.ErrorCodeFlowControl, Reason: "extconnect: window size overflow"}
+		}
+	}
+
+	c.cond.Broadcast()
+
+	return nil
+}
+
+// data buffers the payload of a Data frame for its Stream. Padding, and Data
+// on any Stream which has been closed or reset, is immediately returned to
+// the flow-control windows.
+func (c *Conn) data(f *frames.Data) error {
+	c.mu.Lock()
+
+	if f.StreamID == 0 || f.StreamID > c.lastID {
+		c.mu.Unlock()
+		return frames.ConnectionError{Code: frames.ErrorCodeProtocol, Reason: "extconnect: data on idle stream"}
+	}
+
+	c.recvWindow -= int64(f.Length)
+	if c.recvWindow < 0 {
+		c.mu.Unlock()
+		return frames.ConnectionError{Code: frames.ErrorCodeFlowControl, Reason: "extconnect: connection window exceeded"}
+	}
+
+	unused := int64(f.Length) - int64(len(f.Data))
+	update := []frames.Frame{}
+
+	// NOTE(jc): Data which causes a Stream error still counts against the
+	// connection flow-control window, so MUST be returned to it.
+	var streamErr error
+
+	if s, ok := c.streams[f.StreamID]; ok {
+		s.recvWindow -= int64(f.Length)
+
+		switch {
+		case s.recvWindow < 0:
+			streamErr = frames.StreamError{StreamID: f.StreamID, Code: frames.ErrorCodeFlowControl, Reason: "extconnect: stream window exceeded"}
+			unused = int64(f.Length)
+
+		case s.ended:
+			streamErr = frames.StreamError{StreamID: f.StreamID, Code: frames.ErrorCodeStreamClosed, Reason: "extconnect: data after end of stream"}
+			unused = int64(f.Length)
+
+		case s.closed:
+			unused = int64(f.Length)
+
+		default:
+			s.buf.Write(f.Data)
+		}
+
+		if streamErr == nil {
+			if f.EndStream {
+				c.endStream(s)
+			} else if unused > 0 {
+				s.recvWindow += unused
+				update = append(update, &frames.WindowUpdate{Header: frames.Header{StreamID: f.StreamID}, Increment: uint32(unused)})
+			}
+		}
+	} else {
+		unused = int64(f.Length)
+	}
+
+	c.recvWindow += unused
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if unused > 0 {
+		update = append([]frames.Frame{&frames.WindowUpdate{Increment: uint32(unused)}}, update...)
+		if err := c.writeFrames(update...); err != nil {
+			return err
+		}
+	}
+
+	return streamErr
+}
+
+// endStream records that the server has ended s, removing it from the
+// connection if it has also been closed by the client. It MUST be called
+// while holding mu.
+func (c *Conn) endStream(s *Stream) {
+	s.ended = true
+	if s.readErr == nil {
+		s.readErr = io.EOF
+	}
+
+	if s.closed {
+		delete(c.streams, s.id)
+	}
+}
+
+// headers decodes the header block of a Headers frame, delivering it to its
+// Stream as either the response or trailers.
+func (c *Conn) headers(f *frames.Headers) error {
+	// NOTE(jc): every header block MUST be decoded, even for closed Streams,
+	// to keep the header compression state synchronised with the server.
+	fields, err := c.dec.DecodeHeaderBlock(f.Block)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f.StreamID == 0 || f.StreamID > c.lastID {
+		return frames.ConnectionError{Code: frames.ErrorCodeProtocol, Reason: "extconnect: headers on idle stream"}
+	}
+
+	s, ok := c.streams[f.StreamID]
+	if !ok {
+		return nil
+	}
+
+	if s.header == nil {
+		s.header = fields
+	}
+
+	if f.EndStream {
+		c.endStream(s)
+	}
+
+	c.cond.Broadcast()
+
+	return nil
+}
+
+// release records n bytes read from a Stream, returning them to the
+// flow-control windows of the connection and the Stream once at least
+// windowUpdateThreshold bytes have been read from each.
+func (c *Conn) release(s *Stream, n int) error {
+	var update []frames.Frame
+
+	c.mu.Lock()
+	c.released += int64(n)
+	if c.released >= windowUpdateThreshold {
+		c.recvWindow += c.released
+		update = append(update, &frames.WindowUpdate{Increment: uint32(c.released)})
+		c.released = 0
+	}
+
+	s.released += int64(n)
+	if s.released >= windowUpdateThreshold && !s.ended && s.readErr == nil {
+		s.recvWindow += s.released
+		update = append(update, &frames.WindowUpdate{Header: frames.Header{StreamID: s.id}, Increment: uint32(s.released)})
+		s.released = 0
+	}
+	c.mu.Unlock()
+
+	if len(update) == 0 {
+		return nil
+	}
+
+	return c.writeFrames(update...)
+}
+
+// Close sends a GoAway frame to the server, ending every Stream, and closes
+// the underlying connection.
+func (c *Conn) Close() error {
+	c.writeFrames(&frames.GoAway{LastStreamID: 0, Code: frames.ErrorCodeNoError})
+	c.fail(ErrClosed)
+
+	return c.conn.Close()
+}
@@ -0,0 +1,111 @@
+// Package extconnect implements the Extended CONNECT method for HTTP/2, used
+// to bootstrap protocols such as WebSockets over a single HTTP/2 Stream as
+// defined in RFC 8441. Any number of such Streams may share the same Conn.
+package extconnect
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jamescun/http2/frames"
+	"github.com/jamescun/http2/hpack"
+)
+
+var (
+	// ErrNotSupported is returned by Dial when the server has not enabled
+	// the Extended CONNECT method with settings.EnableConnectProtocol.
+	ErrNotSupported = errors.New("extconnect: not supported by server")
+
+	// ErrClosed is returned when reading from or writing to a closed Stream.
+	ErrClosed = errors.New("extconnect: stream closed")
+)
+
+// StatusError is returned by Dial when the server responds to an Extended
+// CONNECT request with a non-2xx status.
+type StatusError struct {
+	Status string
+}
+
+func (s StatusError) Error() string {
+	return fmt.Sprintf("extconnect: server responded with status %s", s.Status)
+}
+
+// Request describes an Extended CONNECT request.
+// RFC 8441 Section 4
+type Request struct {
+	// Protocol is the value of the :protocol pseudo-header field, the
+	// protocol to be spoken on the Stream.
+	Protocol string
+
+	// Scheme, Authority and Path are the values of the :scheme, :authority
+	// and :path pseudo-header fields of the target URI.
+	Scheme    string
+	Authority string
+	Path      string
+
+	// Header contains additional, lowercase, header fields.
+	Header []hpack.HeaderField
+}
+
+// WebSocket returns a Request bootstrapping a WebSocket of version 13 over
+// HTTP/2, the scheme is "https" or "http" rather than "wss" or "ws".
+// RFC 8441 Section 5
+func WebSocket(scheme, authority, path string) *Request {
+	return &Request{
+		Protocol:  "websocket",
+		Scheme:    scheme,
+		Authority: authority,
+		Path:      path,
+		Header: []hpack.HeaderField{
+			{Name: "sec-websocket-version", Value: "13"},
+		},
+	}
+}
+
+// fields returns the header fields of an Extended CONNECT request.
+func (r *Request) fields() []hpack.HeaderField {
+	fields := []hpack.HeaderField{
+		{Name: ":method", Value: "CONNECT"},
+		{Name: ":protocol", Value: r.Protocol},
+		{Name: ":scheme", Value: r.Scheme},
+		{Name: ":authority", Value: r.Authority},
+		{Name: ":path", Value: r.Path},
+	}
+
+	return append(fields, r.Header...)
+}
+
+// Dial performs an Extended CONNECT request on a new Stream of c, returning
+// the Stream once the server has responded with a 2xx status. Other Streams
+// of c are unaffected. ErrNotSupported is returned if the server has not
+// enabled the Extended CONNECT method.
+func Dial(c *Conn, req *Request) (*Stream, error) {
+	if !c.Settings().EnableConnectProtocol.Enabled {
+		return nil, ErrNotSupported
+	}
+
+	s, err := c.NewStream(req.fields())
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := s.ReadHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	var status string
+	for _, field := range fields {
+		if field.Name == ":status" {
+			status = field.Value
+		}
+	}
+
+	if !strings.HasPrefix(status, "2") || len(status) != 3 {
+		s.reset(frames.ErrorCodeCancel)
+		return nil, StatusError{Status: status}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,658 @@
+package extconnect
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/jamescun/http2/frames"
+	"github.com/jamescun/http2/hpack"
+	"github.com/jamescun/http2/settings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// server is the server side of a connection used for testing.
+type server struct {
+	t    *testing.T
+	conn net.Conn
+	r    *frames.Reader
+	w    *frames.Writer
+	enc  *hpack.Encoder
+	dec  *hpack.Decoder
+}
+
+func newServer(t *testing.T) (*server, net.Conn) {
+	client, conn := net.Pipe()
+
+	return &server{
+		t:    t,
+		conn: conn,
+		r:    frames.NewReader(conn),
+		w:    frames.NewWriter(conn),
+		enc:  hpack.NewEncoder(),
+		dec:  hpack.NewDecoder(),
+	}, client
+}
+
+// handshake exchanges the connection preface with the client, sending
+// values as the initial Settings of the server.
+func (s *server) handshake(values ...settings.Setting) {
+	b := make([]byte, len(Preface))
+
+	_, err := io.ReadFull(s.conn, b)
+	if assert.NoError(s.t, err) {
+		assert.Equal(s.t, Preface, string(b))
+	}
+
+	frame, err := s.r.ReadFrame()
+	if assert.NoError(s.t, err) && assert.IsType(s.t, &frames.Settings{}, frame) {
+		assert.Equal(s.t, []settings.Setting{
+			settings.EnablePush{Enabled: false},
+			settings.MaxHeaderListSize{Size: hpack.DefaultMaxHeaderListSize},
+		}, frame.(*frames.Settings).Settings)
+	}
+
+	s.write(&frames.Settings{Settings: values})
+
+	frame, err = s.r.ReadFrame()
+	if assert.NoError(s.t, err) && assert.IsType(s.t, &frames.Settings{}, frame) {
+		assert.True(s.t, frame.(*frames.Settings).Ack)
+	}
+}
+
+// accept reads the Extended CONNECT request for the next Stream and responds
+// with status.
+func (s *server) accept(id uint32, status string, endStream bool) {
+	frame := s.read()
+	if assert.IsType(s.t, &frames.Headers{}, frame) {
+		assert.Equal(s.t, id, frame.(*frames.Headers).StreamID)
+
+		_, err := s.dec.DecodeHeaderBlock(frame.(*frames.Headers).Block)
+		assert.NoError(s.t, err)
+	}
+
+	s.writeHeaders(id, endStream, hpack.HeaderField{Name: ":status", Value: status})
+}
+
+// writeHeaders writes fields as the header block of a Headers frame.
+func (s *server) writeHeaders(id uint32, endStream bool, fields ...hpack.HeaderField) {
+	block := s.enc.AppendHeaderBlock(nil, fields)
+	s.write(&frames.Headers{Header: frames.Header{StreamID: id}, EndStream: endStream, EndHeaders: true, Block: block})
+}
+
+// drain discards everything written by the client until it closes the
+// connection.
+func (s *server) drain() {
+	io.Copy(io.Discard, s.conn)
+}
+
+func (s *server) write(f frames.Frame) {
+	assert.NoError(s.t, s.w.WriteFrame(f))
+	assert.NoError(s.t, s.w.Flush())
+}
+
+// writeRaw writes a Frame in the wire format, for Frames the Writer refuses
+// to send.
+func (s *server) writeRaw(b []byte) {
+	_, err := s.conn.Write(b)
+	assert.NoError(s.t, err)
+}
+
+// read returns the next Frame not used for flow control or acknowledgement.
+func (s *server) read() frames.Frame {
+	for {
+		frame, err := s.r.ReadFrame()
+		if !assert.NoError(s.t, err) {
+			return nil
+		}
+
+		switch f := frame.(type) {
+		case *frames.WindowUpdate:
+			continue
+		case *frames.Settings:
+			if f.Ack {
+				continue
+			}
+		}
+
+		return frame
+	}
+}
+
+var enableConnectProtocol = settings.EnableConnectProtocol{Enabled: true}
+
+func TestDial(t *testing.T) {
+	srv, client := newServer(t)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		srv.handshake(enableConnectProtocol)
+
+		frame := srv.read()
+		if assert.IsType(t, &frames.Headers{}, frame) {
+			headers := frame.(*frames.Headers)
+			fields, err := srv.dec.DecodeHeaderBlock(headers.Block)
+			assert.NoError(t, err)
+
+			assert.Equal(t, uint32(1), headers.StreamID)
+			assert.True(t, headers.EndHeaders)
+			assert.False(t, headers.EndStream)
+			assert.Equal(t, []hpack.HeaderField{
+				{Name: ":method", Value: "CONNECT"},
+				{Name: ":protocol", Value: "websocket"},
+				{Name: ":scheme", Value: "https"},
+				{Name: ":authority", Value: "example.com"},
+				{Name: ":path", Value: "/chat"},
+				{Name: "sec-websocket-version", Value: "13"},
+			}, fields)
+		}
+
+		srv.writeHeaders(1, false, hpack.HeaderField{Name: ":status", Value: "200"})
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, Data: []byte("hello")})
+
+		frame = srv.read()
+		if assert.IsType(t, &frames.Data{}, frame) {
+			assert.Equal(t, []byte("world"), frame.(*frames.Data).Data)
+		}
+
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, EndStream: true})
+
+		frame = srv.read()
+		if assert.IsType(t, &frames.Data{}, frame) {
+			assert.True(t, frame.(*frames.Data).EndStream)
+		}
+
+		frame = srv.read()
+		if assert.IsType(t, &frames.GoAway{}, frame) {
+			assert.Equal(t, frames.ErrorCodeNoError, frame.(*frames.GoAway).Code)
+		}
+
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	stream, err := Dial(conn, WebSocket("https", "example.com", "/chat"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, uint32(1), stream.ID())
+
+	fields, err := stream.ReadHeader()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []hpack.HeaderField{{Name: ":status", Value: "200"}}, fields)
+	}
+
+	b := make([]byte, 5)
+	_, err = io.ReadFull(stream, b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "hello", string(b))
+	}
+
+	n, err := stream.Write([]byte("world"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5, n)
+	}
+
+	_, err = stream.Read(b)
+	assert.Equal(t, io.EOF, err)
+
+	assert.NoError(t, stream.Close())
+	assert.NoError(t, conn.Close())
+
+	<-done
+}
+
+func TestDialSharedConn(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake(enableConnectProtocol)
+		srv.accept(1, "200", false)
+		srv.accept(3, "200", false)
+
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 3}, Data: []byte("three")})
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, Data: []byte("one")})
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	first, err := Dial(conn, WebSocket("https", "example.com", "/chat"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	second, err := Dial(conn, WebSocket("https", "example.com", "/news"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, uint32(1), first.ID())
+	assert.Equal(t, uint32(3), second.ID())
+
+	b := make([]byte, 5)
+	n, err := second.Read(b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "three", string(b[:n]))
+	}
+
+	n, err = first.Read(b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "one", string(b[:n]))
+	}
+}
+
+func TestDialNotSupported(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake()
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	_, err = Dial(conn, WebSocket("https", "example.com", "/chat"))
+	assert.Equal(t, ErrNotSupported, err)
+}
+
+func TestDialStatus(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake(enableConnectProtocol)
+		srv.accept(1, "400", true)
+
+		frame := srv.read()
+		if assert.IsType(t, &frames.ResetStream{}, frame) {
+			assert.Equal(t, uint32(1), frame.(*frames.ResetStream).StreamID)
+			assert.Equal(t, frames.ErrorCodeCancel, frame.(*frames.ResetStream).Code)
+		}
+
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	_, err = Dial(conn, WebSocket("https", "example.com", "/chat"))
+	assert.Equal(t, StatusError{Status: "400"}, err)
+}
+
+func TestConnIgnoreFrame(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake(enableConnectProtocol)
+		srv.accept(1, "200", false)
+
+		// NOTE(jc): Origin frames MUST be ignored on any Stream other than
+		// zero, the Writer refuses to send them.
+		srv.writeRaw([]byte{0x00, 0x00, 0x00, 0x0c, 0x00, 0x00, 0x00, 0x00, 0x03})
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, Data: []byte("hello")})
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	stream, err := Dial(conn, WebSocket("https", "example.com", "/chat"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	b := make([]byte, 5)
+	_, err = io.ReadFull(stream, b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "hello", string(b))
+	}
+}
+
+func TestConnStreamError(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake(enableConnectProtocol)
+		srv.accept(1, "200", false)
+		srv.accept(3, "200", false)
+
+		// NOTE(jc): WindowUpdate frames with a zero increment are a Stream
+		// error, the Writer refuses to send them.
+		srv.writeRaw([]byte{0x00, 0x00, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00})
+
+		frame := srv.read()
+		if assert.IsType(t, &frames.ResetStream{}, frame) {
+			assert.Equal(t, uint32(3), frame.(*frames.ResetStream).StreamID)
+			assert.Equal(t, frames.ErrorCodeProtocol, frame.(*frames.ResetStream).Code)
+		}
+
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, Data: []byte("hello")})
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	first, err := Dial(conn, WebSocket("https", "example.com", "/chat"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	second, err := Dial(conn, WebSocket("https", "example.com", "/news"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	b := make([]byte, 5)
+	_, err = io.ReadFull(first, b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "hello", string(b))
+	}
+
+	_, err = second.Read(b)
+	assert.ErrorAs(t, err, &frames.StreamError{})
+}
+
+func TestConnDataAfterEndStream(t *testing.T) {
+	srv, client := newServer(t)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		srv.handshake(enableConnectProtocol)
+		srv.accept(1, "200", false)
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, EndStream: true})
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, Data: []byte("hello")})
+
+		// NOTE(jc): discarded Data MUST still be returned to the connection
+		// flow-control window.
+		frame, err := srv.r.ReadFrame()
+		if assert.NoError(t, err) && assert.IsType(t, &frames.WindowUpdate{}, frame) {
+			assert.Equal(t, uint32(0), frame.(*frames.WindowUpdate).StreamID)
+			assert.Equal(t, uint32(5), frame.(*frames.WindowUpdate).Increment)
+		}
+
+		frame, err = srv.r.ReadFrame()
+		if assert.NoError(t, err) && assert.IsType(t, &frames.ResetStream{}, frame) {
+			assert.Equal(t, uint32(1), frame.(*frames.ResetStream).StreamID)
+			assert.Equal(t, frames.ErrorCodeStreamClosed, frame.(*frames.ResetStream).Code)
+		}
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	_, err = Dial(conn, WebSocket("https", "example.com", "/chat"))
+	assert.NoError(t, err)
+
+	<-done
+	go srv.drain()
+}
+
+func TestConnPushPromise(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake(enableConnectProtocol)
+		srv.accept(1, "200", false)
+		srv.write(&frames.PushPromise{Header: frames.Header{StreamID: 1}, EndHeaders: true, PromisedStreamID: 2})
+
+		frame := srv.read()
+		if assert.IsType(t, &frames.GoAway{}, frame) {
+			assert.Equal(t, frames.ErrorCodeProtocol, frame.(*frames.GoAway).Code)
+		}
+
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	stream, err := Dial(conn, WebSocket("https", "example.com", "/chat"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = stream.Read(make([]byte, 5))
+	assert.Equal(t, frames.ConnectionError{Code: frames.ErrorCodeProtocol, Reason: "extconnect: push promise received"}, err)
+}
+
+func TestConnDisableConnectProtocol(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake(enableConnectProtocol)
+		srv.accept(1, "200", false)
+		srv.write(&frames.Settings{Settings: []settings.Setting{
+			settings.EnableConnectProtocol{Enabled: false},
+		}})
+
+		frame := srv.read()
+		if assert.IsType(t, &frames.GoAway{}, frame) {
+			assert.Equal(t, frames.ErrorCodeProtocol, frame.(*frames.GoAway).Code)
+		}
+
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	stream, err := Dial(conn, WebSocket("https", "example.com", "/chat"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = stream.Read(make([]byte, 5))
+	assert.Equal(t, frames.ConnectionError{Code: frames.ErrorCodeProtocol, Reason: "extconnect: extended connect disabled"}, err)
+}
+
+func TestStreamRead(t *testing.T) {
+	srv, client := newServer(t)
+
+	payload := bytes.Repeat([]byte("a"), 16384)
+
+	go func() {
+		srv.handshake(enableConnectProtocol)
+		srv.accept(1, "200", false)
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, Data: []byte("hello")})
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, Data: payload})
+		srv.write(&frames.Data{Header: frames.Header{StreamID: 1}, Data: payload})
+
+		// NOTE(jc): reading into an empty buffer MUST NOT send a
+		// WindowUpdate, as a zero increment is a Stream error, and small
+		// reads are batched until windowUpdateThreshold.
+		var increment uint32
+
+		for _, id := range []uint32{0, 1} {
+			frame, err := srv.r.ReadFrame()
+			if assert.NoError(t, err) && assert.IsType(t, &frames.WindowUpdate{}, frame) {
+				assert.Equal(t, id, frame.(*frames.WindowUpdate).StreamID)
+				assert.GreaterOrEqual(t, frame.(*frames.WindowUpdate).Increment, uint32(windowUpdateThreshold))
+
+				if id == 0 {
+					increment = frame.(*frames.WindowUpdate).Increment
+				} else {
+					assert.Equal(t, increment, frame.(*frames.WindowUpdate).Increment)
+				}
+			}
+		}
+
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	stream, err := Dial(conn, WebSocket("https", "example.com", "/chat"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	n, err := stream.Read(nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, n)
+	}
+
+	b := make([]byte, 5)
+	_, err = io.ReadFull(stream, b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "hello", string(b))
+	}
+
+	b = make([]byte, 2*len(payload))
+	_, err = io.ReadFull(stream, b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, append(payload, payload...), b)
+	}
+}
+
+func TestStreamWriteFlowControl(t *testing.T) {
+	srv, client := newServer(t)
+
+	received := make(chan int)
+
+	go func() {
+		srv.handshake(enableConnectProtocol, settings.InitialWindowSize{Size: 4})
+		srv.accept(1, "200", false)
+
+		var n int
+
+		frame := srv.read()
+		if assert.IsType(t, &frames.Data{}, frame) {
+			n += len(frame.(*frames.Data).Data)
+		}
+
+		srv.write(&frames.WindowUpdate{Header: frames.Header{StreamID: 1}, Increment: 16})
+
+		frame = srv.read()
+		if assert.IsType(t, &frames.Data{}, frame) {
+			n += len(frame.(*frames.Data).Data)
+		}
+
+		received <- n
+
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	stream, err := Dial(conn, WebSocket("https", "example.com", "/chat"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	n, err := stream.Write([]byte("hello world"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 11, n)
+	}
+
+	assert.Equal(t, 11, <-received)
+	assert.NoError(t, stream.Close())
+}
+
+func TestConnHeaderTableSize(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake(enableConnectProtocol, settings.HeaderTableSize{Size: 0})
+
+		// NOTE(jc): the first header block after the Settings are
+		// acknowledged MUST signal the new dynamic table size.
+		frame := srv.read()
+		if assert.IsType(t, &frames.Headers{}, frame) {
+			block := frame.(*frames.Headers).Block
+			if assert.NotEmpty(t, block) {
+				assert.Equal(t, byte(0x20), block[0])
+			}
+
+			_, err := srv.dec.DecodeHeaderBlock(block)
+			assert.NoError(t, err)
+		}
+
+		srv.writeHeaders(1, false, hpack.HeaderField{Name: ":status", Value: "200"})
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	_, err = Dial(conn, WebSocket("https", "example.com", "/chat"))
+	assert.NoError(t, err)
+}
+
+func TestConnCompressionError(t *testing.T) {
+	srv, client := newServer(t)
+
+	go func() {
+		srv.handshake(enableConnectProtocol)
+		srv.read()
+
+		// NOTE(jc): index zero is never valid in a header block.
+		srv.write(&frames.Headers{Header: frames.Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x80}})
+
+		frame := srv.read()
+		if assert.IsType(t, &frames.GoAway{}, frame) {
+			assert.Equal(t, frames.ErrorCodeCompression, frame.(*frames.GoAway).Code)
+		}
+
+		srv.drain()
+	}()
+
+	conn, err := NewConn(client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	_, err = Dial(conn, WebSocket("https", "example.com", "/chat"))
+	assert.ErrorAs(t, err, &frames.ConnectionError{})
+}
@@ -0,0 +1,211 @@
+package extconnect
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/jamescun/http2/frames"
+	"github.com/jamescun/http2/hpack"
+)
+
+// Stream is a bidirectional byte stream carried by Data frames on a single
+// HTTP/2 Stream of a Conn, such as one established by an Extended CONNECT
+// request.
+type Stream struct {
+	conn *Conn
+	id   uint32
+
+	// the following fields are guarded by conn.mu.
+	header     []hpack.HeaderField
+	buf        bytes.Buffer
+	readErr    error
+	writeErr   error
+	closed     bool
+	ended      bool
+	sendWindow int64
+	recvWindow int64
+	released   int64
+}
+
+// ID returns the identifier of the Stream.
+func (s *Stream) ID() uint32 {
+	return s.id
+}
+
+// ReadHeader returns the header fields of the response from the server,
+// waiting until they have been received.
+func (s *Stream) ReadHeader() ([]hpack.HeaderField, error) {
+	c := s.conn
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for s.header == nil && s.readErr == nil {
+		c.cond.Wait()
+	}
+
+	if s.header == nil {
+		if s.readErr == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, s.readErr
+	}
+
+	return s.header, nil
+}
+
+// fail records err as the cause of the Stream ending. It MUST be called
+// while holding conn.mu.
+func (s *Stream) fail(err error) {
+	if s.closed {
+		err = ErrClosed
+	}
+
+	if s.readErr == nil || s.readErr == io.EOF {
+		s.readErr = err
+	}
+	if s.writeErr == nil {
+		s.writeErr = err
+	}
+}
+
+// Read reads data sent by the server on the Stream. io.EOF is returned once
+// the server has ended the Stream and all data has been read.
+func (s *Stream) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	c := s.conn
+
+	c.mu.Lock()
+
+	for s.buf.Len() == 0 && s.readErr == nil {
+		c.cond.Wait()
+	}
+
+	if s.buf.Len() == 0 {
+		err := s.readErr
+		c.mu.Unlock()
+
+		return 0, err
+	}
+
+	n, _ := s.buf.Read(p)
+	c.mu.Unlock()
+
+	// NOTE(jc): data is only returned to the flow-control windows once read,
+	// limiting the amount buffered to the initial window size, and only in
+	// batches of windowUpdateThreshold.
+	if err := c.release(s, n); err != nil {
+		c.mu.Lock()
+		if s.writeErr == nil {
+			s.writeErr = err
+		}
+		c.mu.Unlock()
+	}
+
+	return n, nil
+}
+
+// Write sends p to the server on the Stream as one or more Data frames,
+// blocking while the flow-control window of the server is exhausted.
+func (s *Stream) Write(p []byte) (int, error) {
+	c := s.conn
+
+	var written int
+
+	for len(p) > 0 {
+		c.mu.Lock()
+
+		for (c.sendWindow <= 0 || s.sendWindow <= 0) && s.writeErr == nil && !s.closed {
+			c.cond.Wait()
+		}
+
+		if s.closed {
+			c.mu.Unlock()
+			return written, ErrClosed
+		} else if s.writeErr != nil {
+			err := s.writeErr
+			c.mu.Unlock()
+
+			return written, err
+		}
+
+		n := int64(len(p))
+		for _, limit := range []int64{int64(c.peer.MaxFrameSize.Size), c.sendWindow, s.sendWindow} {
+			if n > limit {
+				n = limit
+			}
+		}
+
+		c.sendWindow -= n
+		s.sendWindow -= n
+		c.mu.Unlock()
+
+		err := c.writeFrames(&frames.Data{Header: frames.Header{StreamID: s.id}, Data: p[:n]})
+		if err != nil {
+			return written, err
+		}
+
+		written += int(n)
+		p = p[n:]
+	}
+
+	return written, nil
+}
+
+// Close ends the Stream by sending an empty Data frame with EndStream set,
+// any further data from the server is discarded. The Conn is not closed.
+func (s *Stream) Close() error {
+	c := s.conn
+
+	c.mu.Lock()
+	if s.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+
+	s.closed = true
+	if s.readErr == nil || s.readErr == io.EOF {
+		s.readErr = ErrClosed
+	}
+
+	// NOTE(jc): unread data will never be released by Read, so is returned
+	// to the connection flow-control window immediately.
+	unread := s.buf.Len()
+	s.buf.Reset()
+	c.recvWindow += int64(unread)
+
+	failed := s.writeErr != nil
+	if failed || s.ended {
+		delete(c.streams, s.id)
+	}
+	dead := c.err != nil
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if dead {
+		return nil
+	}
+
+	var update []frames.Frame
+	if unread > 0 {
+		update = append(update, &frames.WindowUpdate{Increment: uint32(unread)})
+	}
+	if !failed {
+		update = append(update, &frames.Data{Header: frames.Header{StreamID: s.id}, EndStream: true})
+	}
+
+	if len(update) == 0 {
+		return nil
+	}
+
+	return c.writeFrames(update...)
+}
+
+// reset ends the Stream abruptly with a ResetStream frame.
+func (s *Stream) reset(code frames.ErrorCode) {
+	s.conn.resetStream(frames.StreamError{StreamID: s.id, Code: code, Reason: "extconnect: stream cancelled"})
+}
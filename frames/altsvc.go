@@ -0,0 +1,86 @@
+package frames
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrAltSvcOrigin is returned when attempting to marshal an AltSvc frame
+// without an Origin on Stream zero, or with an Origin on any other Stream.
+var ErrAltSvcOrigin = errors.New("frames: altsvc origin invalid for stream")
+
+// AltSvc advertises alternative services, such as HTTP/3 endpoints, that
+// are authoritative for an origin. On Stream zero Origin MUST be set, on any
+// other Stream Origin MUST be empty and the alternative services apply to the
+// origin of that Stream.
+// RFC 7838 Section 4
+type AltSvc struct {
+	Header
+
+	// Origin is the ASCII serialization of the origin the alternative
+	// services apply to, as defined in RFC 6454 Section 6.2.
+	Origin string
+
+	// FieldValue is the value of the Alt-Svc header field, described in
+	// RFC 7838 Section 3.
+	FieldValue string
+}
+
+// MarshalFrame marshals AltSvc into the wire format.
+func (a *AltSvc) MarshalFrame(hdr *Header) ([]byte, error) {
+	if err := a.validate(); err != nil {
+		return nil, err
+	}
+
+	return marshalFrame(a, hdr)
+}
+
+// AppendFrame appends AltSvc, including its Header, in the wire format to b.
+func (a *AltSvc) AppendFrame(b []byte) ([]byte, error) {
+	if err := a.validate(); err != nil {
+		return b, err
+	}
+
+	return appendFrame(a, b)
+}
+
+func (a *AltSvc) appendPayload(b []byte) (Header, []byte) {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(a.Origin)))
+	b = append(b, a.Origin...)
+	b = append(b, a.FieldValue...)
+
+	return Header{Type: TypeAltSvc, StreamID: a.Header.StreamID}, b
+}
+
+func (a *AltSvc) validate() error {
+	if len(a.Origin) > 0xFFFF {
+		return ErrFrameTooBig
+	} else if (a.Header.StreamID == 0) == (a.Origin == "") {
+		return ErrAltSvcOrigin
+	}
+
+	return nil
+}
+
+// UnmarshalFrame unmarshals AltSvc from the wire format. ErrIgnoreFrame is
+// returned if Origin is empty on Stream zero or set on any other Stream.
+func (a *AltSvc) UnmarshalFrame(hdr *Header, b []byte) error {
+	if len(b) < 2 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "altsvc: invalid length"}
+	}
+
+	n := int(binary.BigEndian.Uint16(b))
+	if n > len(b)-2 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "altsvc: invalid origin length"}
+	}
+
+	if (hdr.StreamID == 0) == (n == 0) {
+		return ErrIgnoreFrame
+	}
+
+	a.Header = *hdr
+	a.Origin = string(b[2 : 2+n])
+	a.FieldValue = string(b[2+n:])
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package frames
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAltSvcMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		AltSvc *AltSvc
+		Header *Header
+		Bytes  []byte
+		Error  error
+	}{
+		{
+			"Origin",
+			&AltSvc{Origin: "https://a.b", FieldValue: `h3=":443"`},
+			&Header{Length: 22, Type: TypeAltSvc},
+			append([]byte{0x00, 0x0B}, "https://a.b"+`h3=":443"`...),
+			nil,
+		},
+		{
+			"Stream",
+			&AltSvc{Header: Header{StreamID: 3}, FieldValue: "clear"},
+			&Header{Length: 7, Type: TypeAltSvc, StreamID: 3},
+			append([]byte{0x00, 0x00}, "clear"...),
+			nil,
+		},
+		{
+			"OriginMissing",
+			&AltSvc{FieldValue: "clear"},
+			nil,
+			nil,
+			ErrAltSvcOrigin,
+		},
+		{
+			"OriginOnStream",
+			&AltSvc{Header: Header{StreamID: 3}, Origin: "https://a.b", FieldValue: "clear"},
+			nil,
+			nil,
+			ErrAltSvcOrigin,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.AltSvc.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAltSvcUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Header *Header
+		Bytes  []byte
+		AltSvc *AltSvc
+		Error  error
+	}{
+		{
+			"Origin",
+			&Header{Length: 22, Type: TypeAltSvc},
+			append([]byte{0x00, 0x0B}, "https://a.b"+`h3=":443"`...),
+			&AltSvc{
+				Header:     Header{Length: 22, Type: TypeAltSvc},
+				Origin:     "https://a.b",
+				FieldValue: `h3=":443"`,
+			},
+			nil,
+		},
+		{
+			"Stream",
+			&Header{Length: 7, Type: TypeAltSvc, StreamID: 3},
+			append([]byte{0x00, 0x00}, "clear"...),
+			&AltSvc{
+				Header:     Header{Length: 7, Type: TypeAltSvc, StreamID: 3},
+				FieldValue: "clear",
+			},
+			nil,
+		},
+		{
+			"OriginMissing",
+			&Header{Length: 7, Type: TypeAltSvc},
+			append([]byte{0x00, 0x00}, "clear"...),
+			nil,
+			ErrIgnoreFrame,
+		},
+		{
+			"OriginOnStream",
+			&Header{Length: 8, Type: TypeAltSvc, StreamID: 3},
+			append([]byte{0x00, 0x01, 'a'}, "clear"...),
+			nil,
+			ErrIgnoreFrame,
+		},
+		{
+			"Short",
+			&Header{Length: 1, Type: TypeAltSvc},
+			[]byte{0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "altsvc: invalid length"},
+		},
+		{
+			"OriginLength",
+			&Header{Length: 3, Type: TypeAltSvc},
+			[]byte{0x00, 0x02, 'a'},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "altsvc: invalid origin length"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(AltSvc)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.AltSvc, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package frames
+
+import (
+	"fmt"
+)
+
+// ErrorCode is used in ResetStream and GoAway frames to convey the reason for
+// a stream or connection error. Unknown or unsupported error codes MUST NOT
+// trigger any special behavior, and MAY be treated as ErrorCodeInternal.
+// RFC 7540 Section 7
+type ErrorCode uint32
+
+const (
+	// ErrorCodeNoError (0x0) indicates a condition that is not the result of
+	// an error, such as a graceful shutdown.
+	ErrorCodeNoError = ErrorCode(0x0)
+
+	// ErrorCodeProtocol (0x1) indicates the endpoint detected an unspecific
+	// protocol error.
+	ErrorCodeProtocol = ErrorCode(0x1)
+
+	// ErrorCodeInternal (0x2) indicates the endpoint encountered an unexpected
+	// internal error.
+	ErrorCodeInternal = ErrorCode(0x2)
+
+	// ErrorCodeFlowControl (0x3) indicates the endpoint detected that its peer
+	// violated the flow-control protocol.
+	ErrorCodeFlowControl = ErrorCode(0x3)
+
+	// ErrorCodeSettingsTimeout (0x4) indicates the endpoint sent a Settings
+	// frame but did not receive a response in a timely manner.
+	ErrorCodeSettingsTimeout = ErrorCode(0x4)
+
+	// ErrorCodeStreamClosed (0x5) indicates the endpoint received a frame
+	// after a stream was half-closed.
+	ErrorCodeStreamClosed = ErrorCode(0x5)
+
+	// ErrorCodeFrameSize (0x6) indicates the endpoint received a frame with an
+	// invalid size.
+	ErrorCodeFrameSize = ErrorCode(0x6)
+
+	// ErrorCodeRefusedStream (0x7) indicates the endpoint refused the stream
+	// prior to performing any application processing.
+	ErrorCodeRefusedStream = ErrorCode(0x7)
+
+	// ErrorCodeCancel (0x8) indicates the stream is no longer needed.
+	ErrorCodeCancel = ErrorCode(0x8)
+
+	// ErrorCodeCompression (0x9) indicates the endpoint is unable to maintain
+	// the header compression context for the connection.
+	ErrorCodeCompression = ErrorCode(0x9)
+
+	// ErrorCodeConnect (0xa) indicates the connection established in response
+	// to a CONNECT request was reset or abnormally closed.
+	ErrorCodeConnect = ErrorCode(0xa)
+
+	// ErrorCodeEnhanceYourCalm (0xb) indicates the endpoint detected that its
+	// peer is exhibiting a behavior that might be generating excessive load.
+	ErrorCodeEnhanceYourCalm = ErrorCode(0xb)
+
+	// ErrorCodeInadequateSecurity (0xc) indicates the underlying transport
+	// has properties that do not meet minimum security requirements.
+	ErrorCodeInadequateSecurity = ErrorCode(0xc)
+
+	// ErrorCodeHTTP11Required (0xd) indicates the endpoint requires that
+	// HTTP/1.1 be used instead of HTTP/2.
+	ErrorCodeHTTP11Required = ErrorCode(0xd)
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	ErrorCodeNoError:            "NO_ERROR",
+	ErrorCodeProtocol:           "PROTOCOL_ERROR",
+	ErrorCodeInternal:           "INTERNAL_ERROR",
+	ErrorCodeFlowControl:        "FLOW_CONTROL_ERROR",
+	ErrorCodeSettingsTimeout:    "SETTINGS_TIMEOUT",
+	ErrorCodeStreamClosed:       "STREAM_CLOSED",
+	ErrorCodeFrameSize:          "FRAME_SIZE_ERROR",
+	ErrorCodeRefusedStream:      "REFUSED_STREAM",
+	ErrorCodeCancel:             "CANCEL",
+	ErrorCodeCompression:        "COMPRESSION_ERROR",
+	ErrorCodeConnect:            "CONNECT_ERROR",
+	ErrorCodeEnhanceYourCalm:    "ENHANCE_YOUR_CALM",
+	ErrorCodeInadequateSecurity: "INADEQUATE_SECURITY",
+	ErrorCodeHTTP11Required:     "HTTP_1_1_REQUIRED",
+}
+
+// String returns the name of an ErrorCode as defined in RFC 7540 Section 7,
+// unknown error codes are returned in hexadecimal.
+func (e ErrorCode) String() string {
+	if name, ok := errorCodeNames[e]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("UNKNOWN_ERROR(0x%x)", uint32(e))
+}
+
+// ConnectionError is returned when an error is encountered that makes further
+// processing of the connection impossible. The receiver SHOULD send a GoAway
+// frame with Code before closing the connection.
+// RFC 7540 Section 5.4.1
+type ConnectionError struct {
+	Code   ErrorCode
+	Reason string
+}
+
+func (c ConnectionError) Error() string {
+	return fmt.Sprintf("frames: connection error: %s: %s", c.Code, c.Reason)
+}
+
+// StreamError is returned when an error is encountered that affects only a
+// single stream. The receiver SHOULD send a ResetStream frame with Code on
+// StreamID, the connection remains usable.
+// RFC 7540 Section 5.4.2
+type StreamError struct {
+	StreamID uint32
+	Code     ErrorCode
+	Reason   string
+}
+
+func (s StreamError) Error() string {
+	return fmt.Sprintf("frames: stream error: stream %d: %s: %s", s.StreamID, s.Code, s.Reason)
+}
@@ -0,0 +1,40 @@
+package frames
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCodeString(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Code   ErrorCode
+		String string
+	}{
+		{"NoError", ErrorCodeNoError, "NO_ERROR"},
+		{"Protocol", ErrorCodeProtocol, "PROTOCOL_ERROR"},
+		{"FlowControl", ErrorCodeFlowControl, "FLOW_CONTROL_ERROR"},
+		{"FrameSize", ErrorCodeFrameSize, "FRAME_SIZE_ERROR"},
+		{"HTTP11Required", ErrorCodeHTTP11Required, "HTTP_1_1_REQUIRED"},
+		{"Unknown", ErrorCode(0xff), "UNKNOWN_ERROR(0xff)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.String, test.Code.String())
+		})
+	}
+}
+
+func TestConnectionError(t *testing.T) {
+	err := ConnectionError{Code: ErrorCodeProtocol, Reason: "ping: non-zero stream id"}
+
+	assert.Equal(t, "frames: connection error: PROTOCOL_ERROR: ping: non-zero stream id", err.Error())
+}
+
+func TestStreamError(t *testing.T) {
+	err := StreamError{StreamID: 3, Code: ErrorCodeCancel, Reason: "cancelled"}
+
+	assert.Equal(t, "frames: stream error: stream 3: CANCEL: cancelled", err.Error())
+}
@@ -0,0 +1,85 @@
+package frames
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	extensionsMu sync.RWMutex
+	extensions   = make(map[Type]func() Frame)
+)
+
+// RegisterFrame registers fn to construct Frames of extension Type t, which
+// are then decoded by Reader using their UnmarshalFrame method instead of
+// being returned as an UnknownFrame. RegisterFrame panics if t is already
+// understood by this package or has already been registered.
+// RFC 7540 Section 5.5
+func RegisterFrame(t Type, fn func() Frame) {
+	if builtinFrame(t) != nil {
+		panic(fmt.Sprintf("frames: cannot register builtin frame type 0x%x", uint8(t)))
+	} else if fn == nil {
+		panic("frames: nil frame constructor")
+	}
+
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+
+	if _, ok := extensions[t]; ok {
+		panic(fmt.Sprintf("frames: frame type 0x%x already registered", uint8(t)))
+	}
+
+	extensions[t] = fn
+}
+
+// newExtensionFrame returns a new Frame for extension Type t if one has
+// been registered, otherwise it returns an UnknownFrame.
+func newExtensionFrame(t Type) Frame {
+	extensionsMu.RLock()
+	fn, ok := extensions[t]
+	extensionsMu.RUnlock()
+
+	if ok {
+		return fn()
+	}
+
+	return new(UnknownFrame)
+}
+
+// UnknownFrame is a Frame with a Type not understood by this package, such
+// as an unregistered extension frame. Its Header and payload are preserved so
+// it may be forwarded unmodified, otherwise it MUST be ignored.
+// RFC 7540 Section 4.1
+type UnknownFrame struct {
+	Header
+
+	// Payload is the raw payload of the Frame.
+	Payload []byte
+}
+
+// MarshalFrame marshals UnknownFrame into the wire format, preserving the
+// Type, Flags and StreamID of its Header.
+func (u *UnknownFrame) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(u, hdr)
+}
+
+// AppendFrame appends UnknownFrame, including its Header, in the wire format
+// to b.
+func (u *UnknownFrame) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(u, b)
+}
+
+func (u *UnknownFrame) appendPayload(b []byte) (Header, []byte) {
+	hdr := Header{Type: u.Header.Type, Flags: u.Header.Flags, StreamID: u.Header.StreamID}
+
+	return hdr, append(b, u.Payload...)
+}
+
+// UnmarshalFrame unmarshals UnknownFrame from the wire format.
+func (u *UnknownFrame) UnmarshalFrame(hdr *Header, b []byte) error {
+	u.Header = *hdr
+	u.Payload = make([]byte, len(b))
+	copy(u.Payload, b)
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package frames
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// typeExperiment is an extension frame type used only by tests.
+const typeExperiment = Type(0xF0)
+
+type experiment struct {
+	Header
+
+	Value byte
+}
+
+func (e *experiment) MarshalFrame(hdr *Header) ([]byte, error) {
+	hdr.Length = 1
+	hdr.Type = typeExperiment
+	hdr.StreamID = e.Header.StreamID
+
+	return []byte{e.Value}, nil
+}
+
+func (e *experiment) UnmarshalFrame(hdr *Header, b []byte) error {
+	if len(b) != 1 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "experiment: invalid length"}
+	}
+
+	e.Header = *hdr
+	e.Value = b[0]
+
+	return nil
+}
+
+func TestRegisterFrame(t *testing.T) {
+	RegisterFrame(typeExperiment, func() Frame { return new(experiment) })
+	t.Cleanup(func() {
+		extensionsMu.Lock()
+		delete(extensions, typeExperiment)
+		extensionsMu.Unlock()
+	})
+
+	r := NewReader(bytes.NewReader([]byte{
+		0x00, 0x00, 0x01, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x01, 0x2A,
+		0x00, 0x00, 0x01, 0xF1, 0x00, 0x00, 0x00, 0x00, 0x01, 0x2A,
+	}))
+
+	frame, err := r.ReadFrame()
+	if assert.NoError(t, err) {
+		assert.Equal(t, &experiment{Header: Header{Length: 1, Type: typeExperiment, StreamID: 1}, Value: 0x2A}, frame)
+	}
+
+	frame, err = r.ReadFrame()
+	if assert.NoError(t, err) {
+		assert.Equal(t, &UnknownFrame{Header: Header{Length: 1, Type: Type(0xF1), StreamID: 1}, Payload: []byte{0x2A}}, frame)
+	}
+
+	assert.Panics(t, func() {
+		RegisterFrame(typeExperiment, func() Frame { return new(experiment) })
+	})
+	assert.Panics(t, func() {
+		RegisterFrame(TypeData, func() Frame { return new(Data) })
+	})
+}
+
+func TestUnknownFrameMarshalFrame(t *testing.T) {
+	frame := &UnknownFrame{
+		Header:  Header{Type: Type(0xFF), Flags: Flags(0x01), StreamID: 3},
+		Payload: []byte{0xAA, 0xBB},
+	}
+
+	hdr := new(Header)
+	bytes, err := frame.MarshalFrame(hdr)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Header{Length: 2, Type: Type(0xFF), Flags: Flags(0x01), StreamID: 3}, hdr)
+		assert.Equal(t, []byte{0xAA, 0xBB}, bytes)
+	}
+}
+
+func TestUnknownFrameUnmarshalFrame(t *testing.T) {
+	frame := new(UnknownFrame)
+	err := frame.UnmarshalFrame(&Header{Length: 2, Type: Type(0xFF), Flags: Flags(0x01), StreamID: 3}, []byte{0xAA, 0xBB})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, &UnknownFrame{
+			Header:  Header{Length: 2, Type: Type(0xFF), Flags: Flags(0x01), StreamID: 3},
+			Payload: []byte{0xAA, 0xBB},
+		}, frame)
+	}
+}
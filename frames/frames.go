@@ -3,8 +3,8 @@
 package frames
 
 import (
+	"encoding/binary"
 	"errors"
-	"fmt"
 
 	"github.com/jamescun/http2/settings"
 )
@@ -21,6 +21,10 @@ var (
 	// ErrShortFrame is returned when attempting to unmarshal a Frame but not
 	// enough bytes are available.
 	ErrShortFrame = errors.New("frames: too short")
+
+	// ErrIgnoreFrame is returned when attempting to unmarshal a Frame that is
+	// invalid but which receivers MUST ignore rather than treat as an error.
+	ErrIgnoreFrame = errors.New("frames: invalid frame ignored")
 )
 
 // Frame is implemented by all HTTP/2 Frame definitions, as defined in RFC 7540
@@ -35,8 +39,80 @@ type Frame interface {
 	UnmarshalFrame(*Header, []byte) error
 }
 
+// Appender is implemented by Frames that can marshal themselves, including
+// their Header, directly into a caller-owned buffer.
+type Appender interface {
+	// AppendFrame appends the Header and Frame in the wire format to b and
+	// returns the extended buffer.
+	AppendFrame(b []byte) ([]byte, error)
+}
+
+// AppendFrame marshals Frame f, including its Header, to the wire format,
+// appends it to b and returns the extended buffer. Frames that do not
+// implement Appender are marshalled with MarshalFrame.
+func AppendFrame(b []byte, f Frame) ([]byte, error) {
+	if a, ok := f.(Appender); ok {
+		return a.AppendFrame(b)
+	}
+
+	var hdr Header
+
+	payload, err := f.MarshalFrame(&hdr)
+	if err != nil {
+		return b, err
+	}
+
+	if hdr.Length >= (1 << 24) {
+		return b, ErrFrameTooBig
+	}
+
+	b = hdr.appendFrameHeader(b)
+
+	return append(b, payload...), nil
+}
+
+// payloadAppender is implemented by all Frames in this package, it appends
+// the Frame payload to b and returns the Header describing it, excluding
+// Length.
+type payloadAppender interface {
+	appendPayload(b []byte) (Header, []byte)
+}
+
+// marshalFrame implements Frame.MarshalFrame for a payloadAppender.
+func marshalFrame(f payloadAppender, hdr *Header) ([]byte, error) {
+	h, b := f.appendPayload([]byte{})
+
+	hdr.Length = uint32(len(b))
+	hdr.Type = h.Type
+	hdr.Flags = h.Flags
+	hdr.StreamID = h.StreamID
+
+	return b, nil
+}
+
+// appendFrame implements Appender.AppendFrame for a payloadAppender.
+func appendFrame(f payloadAppender, b []byte) ([]byte, error) {
+	n := len(b)
+
+	// NOTE(jc): reserve space for the Header, it is written once the length
+	// of the payload is known.
+	b = append(b, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+
+	hdr, b := f.appendPayload(b)
+
+	hdr.Length = uint32(len(b) - n - HeaderLength)
+	if hdr.Length >= (1 << 24) {
+		return b[:n], ErrFrameTooBig
+	}
+
+	hdr.putFrameHeader(b[n:])
+
+	return b, nil
+}
+
 // Type is the unique identifier given to each Frame. FrameTypes greater than
-// 0x09 are considered extensions and MUST be ignored if not understood.
+// 0x09 are considered extensions and MUST be ignored if not understood, see
+// RegisterFrame and UnknownFrame.
 // RFC 7540 Section 4.1
 type Type uint8
 
@@ -70,6 +146,15 @@ const (
 
 	// TypeContinuation (0x9) is defined by RFC 7540 Section 6.10.
 	TypeContinuation = Type(0x9)
+
+	// TypeAltSvc (0xa) is defined by RFC 7838 Section 4.
+	TypeAltSvc = Type(0xa)
+
+	// TypeOrigin (0xc) is defined by RFC 8336 Section 2.
+	TypeOrigin = Type(0xc)
+
+	// TypePriorityUpdate (0x10) is defined by RFC 9218 Section 7.1.
+	TypePriorityUpdate = Type(0x10)
 )
 
 // Flags are Frame specific options set on the FrameHeader.
@@ -77,8 +162,8 @@ const (
 type Flags uint8
 
 // Set sets Flags v on Flags f.
-func (f Flags) Set(v Flags) {
-	f = f | v
+func (f *Flags) Set(v Flags) {
+	*f = *f | v
 }
 
 // Has returns true if Flags f contains Flags v.
@@ -108,14 +193,28 @@ func (h *Header) MarshalFrameHeader() ([]byte, error) {
 		return nil, ErrFrameTooBig
 	}
 
-	b := make([]byte, HeaderLength)
+	return h.appendFrameHeader(make([]byte, 0, HeaderLength)), nil
+}
+
+// appendFrameHeader appends Header in the wire format to b, the caller is
+// responsible for checking Length.
+func (h *Header) appendFrameHeader(b []byte) []byte {
+	n := len(b)
+	b = append(b, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+
+	h.putFrameHeader(b[n:])
+
+	return b
+}
 
+// putFrameHeader writes Header in the wire format to b, which must be at
+// least HeaderLength bytes.
+func (h *Header) putFrameHeader(b []byte) {
+	_ = b[8] // bounds check hint to compiler; see golang.org/issue/14808
 	putUint24(b, h.Length)
 	b[3] = byte(h.Type)
 	b[4] = byte(h.Flags)
 	putUint31(b[5:], h.StreamID)
-
-	return b, nil
 }
 
 // UnmarshalFrameHeader unmarshals a Header from the wire format.
@@ -154,49 +253,70 @@ type Settings struct {
 
 // MarshalFrame marshals Settings into the wire format.
 func (s *Settings) MarshalFrame(hdr *Header) ([]byte, error) {
-	hdr.Length = uint32(6 * len(s.Settings))
-	hdr.Type = TypeSettings
-	hdr.StreamID = 0
+	return marshalFrame(s, hdr)
+}
+
+// AppendFrame appends Settings, including its Header, in the wire format to b.
+func (s *Settings) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(s, b)
+}
+
+func (s *Settings) appendPayload(b []byte) (Header, []byte) {
+	hdr := Header{Type: TypeSettings}
 
 	if s.Ack {
 		hdr.Flags = FlagSettingsAck
 	}
 
-	b := make([]byte, 0, 6*len(s.Settings))
-
 	for _, setting := range s.Settings {
 		b = settings.AppendSetting(b, setting)
 	}
 
-	return b, nil
+	return hdr, b
 }
 
 // UnmarshalFrame unmarshals Settings from the wire format.
 func (s *Settings) UnmarshalFrame(hdr *Header, b []byte) error {
-	if len(b) == 0 {
-		return nil
+	if hdr.StreamID != 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "settings: non-zero stream id"}
+	} else if hdr.Flags.Has(FlagSettingsAck) && len(b) != 0 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "settings: ack with payload"}
 	}
 
 	// NOTE(jc): settings identifiers and values are always a multiple of six.
 	if len(b)%6 != 0 {
-		return ErrShortFrame
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "settings: invalid length"}
+	}
+
+	if hdr.Flags.Has(FlagSettingsAck) {
+		s.Ack = true
 	}
 
 	s.Header = *hdr
+
+	if len(b) == 0 {
+		return nil
+	}
+
 	s.Settings = make([]settings.Setting, 0, len(b)/6)
 
 	for len(b) > 0 {
+		// NOTE(jc): unknown settings MUST be ignored by receivers, but are
+		// returned by ParseSetting as settings.Unknown and preserved so
+		// proxies may forward or log them.
 		setting, err := settings.ParseSetting(b)
+		if err == nil || err == settings.ErrUnknown {
+			err = setting.Validate()
+		}
 
-		b = b[6:]
-
-		if err == settings.ErrUnknown {
-			continue
+		if err == settings.ErrInitialWindowSize {
+			return ConnectionError{Code: ErrorCodeFlowControl, Reason: err.Error()}
 		} else if err != nil {
-			return err
+			return ConnectionError{Code: ErrorCodeProtocol, Reason: err.Error()}
 		}
 
 		s.Settings = append(s.Settings, setting)
+		b = b[6:]
 	}
 
 	return nil
@@ -225,9 +345,6 @@ var (
 
 // Headers is used to initialize a Stream and contains zero or more HPACK
 // header block fragments.
-//
-// NOTE(jc): Padding and Priority are not currently implemented.
-//
 // RFC 7540 Section 6.2
 type Headers struct {
 	Header
@@ -240,18 +357,30 @@ type Headers struct {
 	// other Headers frame or Continuation frame will be sent.
 	EndHeaders bool
 
+	// Priority optionally contains prioritization information for the Stream,
+	// if nil no priority information is sent.
+	Priority *PriorityParam
+
+	// PadLength is the number of bytes of padding following Block. Padding
+	// is sent if PadLength is non-zero or FlagHeadersPadded is set.
+	PadLength uint8
+
 	// Block contains an HPACK header block fragment, described in RFC 7541.
 	Block []byte
 }
 
 // MarshalFrame marshals Headers into the wire format.
 func (h *Headers) MarshalFrame(hdr *Header) ([]byte, error) {
-	// TODO(jc): implement security padding and stream prioritization.
-	if h.Header.Flags.Has(FlagHeadersPadded) {
-		return nil, fmt.Errorf("headers: padding not implemented")
-	} else if h.Header.Flags.Has(FlagHeadersPriority) {
-		return nil, fmt.Errorf("headers: priority not implemented")
-	}
+	return marshalFrame(h, hdr)
+}
+
+// AppendFrame appends Headers, including its Header, in the wire format to b.
+func (h *Headers) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(h, b)
+}
+
+func (h *Headers) appendPayload(b []byte) (Header, []byte) {
+	hdr := Header{Type: TypeHeaders, StreamID: h.Header.StreamID}
 
 	if h.EndStream {
 		hdr.Flags.Set(FlagHeadersEndStream)
@@ -260,24 +389,61 @@ func (h *Headers) MarshalFrame(hdr *Header) ([]byte, error) {
 		hdr.Flags.Set(FlagHeadersEndHeaders)
 	}
 
-	hdr.Type = TypeHeaders
-	hdr.Length = uint32(len(h.Block))
-	hdr.StreamID = h.StreamID
+	padded := h.PadLength > 0 || h.Header.Flags.Has(FlagHeadersPadded)
+	if padded {
+		hdr.Flags.Set(FlagHeadersPadded)
+		b = append(b, h.PadLength)
+	}
 
-	b := make([]byte, len(h.Block))
-	copy(b, h.Block)
+	if h.Priority != nil {
+		hdr.Flags.Set(FlagHeadersPriority)
+		b = appendPriorityParam(b, *h.Priority)
+	}
 
-	return b, nil
+	b = append(b, h.Block...)
+
+	if padded {
+		b = appendPadding(b, h.PadLength)
+	}
+
+	return hdr, b
 }
 
 // UnmarshalFrame unmarshals Headers from the wire format.
 func (h *Headers) UnmarshalFrame(hdr *Header, b []byte) error {
-	// TODO(jc): implement security padding and stream prioritization from
-	// initial Headers frame.
+	return h.unmarshalFrame(hdr, b, false)
+}
+
+func (h *Headers) unmarshalFrame(hdr *Header, b []byte, alias bool) error {
+	if hdr.StreamID == 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "headers: zero stream id"}
+	}
+
 	if hdr.Flags.Has(FlagHeadersPadded) {
-		return fmt.Errorf("headers: padding not implemented")
-	} else if hdr.Flags.Has(FlagHeadersPriority) {
-		return fmt.Errorf("headers: priority not implemented")
+		var fixed int
+		if hdr.Flags.Has(FlagHeadersPriority) {
+			fixed = PriorityParamLength
+		}
+
+		var ok bool
+		b, h.PadLength, ok = trimPadding(b, fixed)
+		if !ok {
+			return ConnectionError{Code: ErrorCodeProtocol, Reason: "headers: invalid padding"}
+		}
+	}
+
+	if hdr.Flags.Has(FlagHeadersPriority) {
+		if len(b) < PriorityParamLength {
+			return ConnectionError{Code: ErrorCodeFrameSize, Reason: "headers: invalid length"}
+		}
+
+		priority := parsePriorityParam(b)
+		if priority.StreamDependency == hdr.StreamID {
+			return StreamError{StreamID: hdr.StreamID, Code: ErrorCodeProtocol, Reason: "headers: stream depends on itself"}
+		}
+
+		h.Priority = &priority
+		b = b[PriorityParamLength:]
 	}
 
 	if hdr.Flags.Has(FlagHeadersEndStream) {
@@ -288,8 +454,7 @@ func (h *Headers) UnmarshalFrame(hdr *Header, b []byte) error {
 	}
 
 	h.Header = *hdr
-	h.Block = make([]byte, len(b))
-	copy(h.Block, b)
+	h.Block = clonePayload(b, alias)
 
 	return nil
 }
@@ -305,9 +470,6 @@ const (
 )
 
 // Data is used to carry request or response data between peers.
-//
-// NOTE(jc): Padding is not currently implemented.
-//
 // RFC 7540 Section 6.1
 type Data struct {
 	Header
@@ -315,36 +477,62 @@ type Data struct {
 	// EndStream indicates this Data frame terminates the Stream.
 	EndStream bool
 
+	// PadLength is the number of bytes of padding following Data. Padding is
+	// sent if PadLength is non-zero or FlagDataPadded is set.
+	PadLength uint8
+
 	// Application data from peer.
 	Data []byte
 }
 
 // MarshalFrame marshals Data into the wire format.
 func (d *Data) MarshalFrame(hdr *Header) ([]byte, error) {
-	// TODO(jc): implement security padding.
-	if d.Header.Flags.Has(FlagDataPadded) {
-		return nil, fmt.Errorf("data: padding not implemented")
-	}
+	return marshalFrame(d, hdr)
+}
+
+// AppendFrame appends Data, including its Header, in the wire format to b.
+func (d *Data) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(d, b)
+}
+
+func (d *Data) appendPayload(b []byte) (Header, []byte) {
+	hdr := Header{Type: TypeData, StreamID: d.Header.StreamID}
 
 	if d.EndStream {
 		hdr.Flags.Set(FlagDataEndStream)
 	}
 
-	hdr.Type = TypeData
-	hdr.Length = uint32(len(d.Data))
-	hdr.StreamID = d.Header.StreamID
+	padded := d.PadLength > 0 || d.Header.Flags.Has(FlagDataPadded)
+	if padded {
+		hdr.Flags.Set(FlagDataPadded)
+		b = append(b, d.PadLength)
+	}
 
-	b := make([]byte, len(d.Data))
-	copy(b, d.Data)
+	b = append(b, d.Data...)
 
-	return b, nil
+	if padded {
+		b = appendPadding(b, d.PadLength)
+	}
+
+	return hdr, b
 }
 
 // UnmarshalFrame unmarshals Data from the wire format.
 func (d *Data) UnmarshalFrame(hdr *Header, b []byte) error {
-	// TODO(jc): implement security padding.
+	return d.unmarshalFrame(hdr, b, false)
+}
+
+func (d *Data) unmarshalFrame(hdr *Header, b []byte, alias bool) error {
+	if hdr.StreamID == 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "data: zero stream id"}
+	}
+
 	if hdr.Flags.Has(FlagDataPadded) {
-		return fmt.Errorf("data: padding not implemented")
+		var ok bool
+		b, d.PadLength, ok = trimPadding(b, 0)
+		if !ok {
+			return ConnectionError{Code: ErrorCodeProtocol, Reason: "data: invalid padding"}
+		}
 	}
 
 	if hdr.Flags.Has(FlagDataEndStream) {
@@ -352,8 +540,7 @@ func (d *Data) UnmarshalFrame(hdr *Header, b []byte) error {
 	}
 
 	d.Header = *hdr
-	d.Data = make([]byte, len(b))
-	copy(d.Data, b)
+	d.Data = clonePayload(b, alias)
 
 	return nil
 }
@@ -365,6 +552,507 @@ const (
 	FlagPingAck = Flags(0x1)
 )
 
+// PingLength is the fixed length of a Ping Frame payload in bytes.
+// RFC 7540 Section 6.7
+const PingLength = 8
+
+// Ping is used to measure round-trip time from the sender and to determine if
+// an idle connection is still functional. Receivers of a Ping frame without
+// Ack set MUST respond with a Ping frame with Ack set and identical Data.
+// RFC 7540 Section 6.7
+type Ping struct {
+	Header
+
+	// Ack indicates this Ping frame is a response to a previously received
+	// Ping frame.
+	Ack bool
+
+	// Data is opaque data that will be echoed by the receiving peer.
+	Data [PingLength]byte
+}
+
+// MarshalFrame marshals Ping into the wire format.
+func (p *Ping) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(p, hdr)
+}
+
+// AppendFrame appends Ping, including its Header, in the wire format to b.
+func (p *Ping) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(p, b)
+}
+
+func (p *Ping) appendPayload(b []byte) (Header, []byte) {
+	hdr := Header{Type: TypePing}
+
+	if p.Ack {
+		hdr.Flags = FlagPingAck
+	}
+
+	return hdr, append(b, p.Data[:]...)
+}
+
+// UnmarshalFrame unmarshals Ping from the wire format.
+func (p *Ping) UnmarshalFrame(hdr *Header, b []byte) error {
+	if hdr.StreamID != 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "ping: non-zero stream id"}
+	} else if len(b) != PingLength {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "ping: invalid length"}
+	}
+
+	if hdr.Flags.Has(FlagPingAck) {
+		p.Ack = true
+	}
+
+	p.Header = *hdr
+	copy(p.Data[:], b)
+
+	return nil
+}
+
+// GoAway initiates the shutdown of a connection or signals a serious error
+// condition. LastStreamID is the highest numbered stream the sender may have
+// processed, streams above it may safely be retried on a new connection.
+// RFC 7540 Section 6.8
+type GoAway struct {
+	Header
+
+	// LastStreamID is the highest numbered stream initiated by the receiver
+	// that the sender has or might take action upon.
+	LastStreamID uint32
+
+	// Code is the reason for closing the connection.
+	Code ErrorCode
+
+	// DebugData is opaque diagnostic data, it carries no semantic value.
+	DebugData []byte
+}
+
+// MarshalFrame marshals GoAway into the wire format.
+func (g *GoAway) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(g, hdr)
+}
+
+// AppendFrame appends GoAway, including its Header, in the wire format to b.
+func (g *GoAway) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(g, b)
+}
+
+func (g *GoAway) appendPayload(b []byte) (Header, []byte) {
+	b = appendUint31(b, g.LastStreamID)
+	b = binary.BigEndian.AppendUint32(b, uint32(g.Code))
+	b = append(b, g.DebugData...)
+
+	return Header{Type: TypeGoAway}, b
+}
+
+// UnmarshalFrame unmarshals GoAway from the wire format.
+func (g *GoAway) UnmarshalFrame(hdr *Header, b []byte) error {
+	if hdr.StreamID != 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "goaway: non-zero stream id"}
+	} else if len(b) < 8 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "goaway: invalid length"}
+	}
+
+	g.Header = *hdr
+	g.LastStreamID = uint31(b)
+	g.Code = ErrorCode(binary.BigEndian.Uint32(b[4:]))
+	g.DebugData = make([]byte, len(b)-8)
+	copy(g.DebugData, b[8:])
+
+	return nil
+}
+
+// ResetStream allows for immediate termination of a Stream, either to cancel
+// it or to signal an error condition.
+// RFC 7540 Section 6.4
+type ResetStream struct {
+	Header
+
+	// Code is the reason for terminating the Stream.
+	Code ErrorCode
+}
+
+// MarshalFrame marshals ResetStream into the wire format.
+func (r *ResetStream) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(r, hdr)
+}
+
+// AppendFrame appends ResetStream, including its Header, in the wire format to b.
+func (r *ResetStream) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(r, b)
+}
+
+func (r *ResetStream) appendPayload(b []byte) (Header, []byte) {
+	b = binary.BigEndian.AppendUint32(b, uint32(r.Code))
+
+	return Header{Type: TypeResetStream, StreamID: r.Header.StreamID}, b
+}
+
+// UnmarshalFrame unmarshals ResetStream from the wire format.
+func (r *ResetStream) UnmarshalFrame(hdr *Header, b []byte) error {
+	if hdr.StreamID == 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "rst_stream: zero stream id"}
+	} else if len(b) != 4 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "rst_stream: invalid length"}
+	}
+
+	r.Header = *hdr
+	r.Code = ErrorCode(binary.BigEndian.Uint32(b))
+
+	return nil
+}
+
+// WindowUpdate implements flow control, informing a peer of the additional
+// number of bytes the sender can transmit on a Stream, or on the connection
+// as a whole when StreamID is zero.
+// RFC 7540 Section 6.9
+type WindowUpdate struct {
+	Header
+
+	// Increment is the number of bytes the sender can transmit in addition
+	// to the existing flow-control window, from 1 to 2^31-1.
+	Increment uint32
+}
+
+// MarshalFrame marshals WindowUpdate into the wire format.
+func (w *WindowUpdate) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(w, hdr)
+}
+
+// AppendFrame appends WindowUpdate, including its Header, in the wire format to b.
+func (w *WindowUpdate) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(w, b)
+}
+
+func (w *WindowUpdate) appendPayload(b []byte) (Header, []byte) {
+	b = appendUint31(b, w.Increment)
+
+	return Header{Type: TypeWindowUpdate, StreamID: w.Header.StreamID}, b
+}
+
+// UnmarshalFrame unmarshals WindowUpdate from the wire format.
+func (w *WindowUpdate) UnmarshalFrame(hdr *Header, b []byte) error {
+	if len(b) != 4 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "window_update: invalid length"}
+	}
+
+	increment := uint31(b)
+	if increment == 0 {
+		if hdr.StreamID == 0 {
+			return ConnectionError{Code: ErrorCodeProtocol, Reason: "window_update: zero increment"}
+		}
+
+		return StreamError{StreamID: hdr.StreamID, Code: ErrorCodeProtocol, Reason: "window_update: zero increment"}
+	}
+
+	w.Header = *hdr
+	w.Increment = increment
+
+	return nil
+}
+
+// PriorityParamLength is the fixed length of a PriorityParam in bytes.
+// RFC 7540 Section 6.3
+const PriorityParamLength = 5
+
+// PriorityParam describes the priority of a Stream relative to other Streams,
+// carried by both Priority and Headers frames.
+// RFC 7540 Section 5.3
+type PriorityParam struct {
+	// Exclusive indicates the Stream becomes the sole dependency of
+	// StreamDependency, adopting any of its existing dependencies.
+	Exclusive bool
+
+	// StreamDependency is the Stream this Stream depends on, or zero for
+	// the root of the dependency tree.
+	StreamDependency uint32
+
+	// Weight is the priority weight of the Stream, as it appears on the wire.
+	// Add one to obtain a weight between 1 and 256.
+	Weight uint8
+}
+
+func parsePriorityParam(b []byte) PriorityParam {
+	_ = b[4] // bounds check hint to compiler; see golang.org/issue/14808
+	return PriorityParam{
+		Exclusive:        b[0]&0x80 != 0,
+		StreamDependency: uint31(b),
+		Weight:           b[4],
+	}
+}
+
+func appendPriorityParam(b []byte, p PriorityParam) []byte {
+	n := len(b)
+	b = appendUint31(b, p.StreamDependency)
+
+	if p.Exclusive {
+		b[n] |= 0x80
+	}
+
+	return append(b, p.Weight)
+}
+
+// Priority specifies the sender-advised priority of a Stream. It can be sent
+// in any Stream state, including idle or closed Streams.
+// RFC 7540 Section 6.3
+type Priority struct {
+	Header
+
+	PriorityParam
+}
+
+// MarshalFrame marshals Priority into the wire format.
+func (p *Priority) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(p, hdr)
+}
+
+// AppendFrame appends Priority, including its Header, in the wire format to b.
+func (p *Priority) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(p, b)
+}
+
+func (p *Priority) appendPayload(b []byte) (Header, []byte) {
+	b = appendPriorityParam(b, p.PriorityParam)
+
+	return Header{Type: TypePriority, StreamID: p.Header.StreamID}, b
+}
+
+// UnmarshalFrame unmarshals Priority from the wire format.
+func (p *Priority) UnmarshalFrame(hdr *Header, b []byte) error {
+	if hdr.StreamID == 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "priority: zero stream id"}
+	} else if len(b) != PriorityParamLength {
+		return StreamError{StreamID: hdr.StreamID, Code: ErrorCodeFrameSize, Reason: "priority: invalid length"}
+	}
+
+	param := parsePriorityParam(b)
+	if param.StreamDependency == hdr.StreamID {
+		return StreamError{StreamID: hdr.StreamID, Code: ErrorCodeProtocol, Reason: "priority: stream depends on itself"}
+	}
+
+	p.Header = *hdr
+	p.PriorityParam = param
+
+	return nil
+}
+
+const (
+	// FlagPushPromiseEndHeaders indicates a PushPromise frame is the last of
+	// the header block and is not followed by any Continuation frames.
+	// RFC 7540 Section 6.6
+	FlagPushPromiseEndHeaders = Flags(0x04)
+
+	// FlagPushPromisePadded indicates a PushPromise frame contains trailing
+	// padding.
+	// RFC 7540 Section 6.6
+	FlagPushPromisePadded = Flags(0x08)
+)
+
+// PushPromise notifies a peer in advance of Streams the sender intends to
+// initiate. Receivers that have disabled push with settings.EnablePush MUST
+// treat receipt of a PushPromise frame as a connection error of type
+// ErrorCodeProtocol, otherwise a promised Stream can be refused by sending a
+// ResetStream frame with ErrorCodeRefusedStream on PromisedStreamID.
+// RFC 7540 Section 6.6
+type PushPromise struct {
+	Header
+
+	// EndHeaders indicates this PushPromise frame contains the entire header
+	// block and no Continuation frames will be sent.
+	EndHeaders bool
+
+	// PadLength is the number of bytes of padding following Block. Padding
+	// is sent if PadLength is non-zero or FlagPushPromisePadded is set.
+	PadLength uint8
+
+	// PromisedStreamID is the Stream reserved by this PushPromise.
+	PromisedStreamID uint32
+
+	// Block contains an HPACK header block fragment, described in RFC 7541.
+	Block []byte
+}
+
+// MarshalFrame marshals PushPromise into the wire format.
+func (p *PushPromise) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(p, hdr)
+}
+
+// AppendFrame appends PushPromise, including its Header, in the wire format to b.
+func (p *PushPromise) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(p, b)
+}
+
+func (p *PushPromise) appendPayload(b []byte) (Header, []byte) {
+	hdr := Header{Type: TypePushPromise, StreamID: p.Header.StreamID}
+
+	if p.EndHeaders {
+		hdr.Flags.Set(FlagPushPromiseEndHeaders)
+	}
+
+	padded := p.PadLength > 0 || p.Header.Flags.Has(FlagPushPromisePadded)
+	if padded {
+		hdr.Flags.Set(FlagPushPromisePadded)
+		b = append(b, p.PadLength)
+	}
+
+	b = appendUint31(b, p.PromisedStreamID)
+	b = append(b, p.Block...)
+
+	if padded {
+		b = appendPadding(b, p.PadLength)
+	}
+
+	return hdr, b
+}
+
+// UnmarshalFrame unmarshals PushPromise from the wire format.
+func (p *PushPromise) UnmarshalFrame(hdr *Header, b []byte) error {
+	return p.unmarshalFrame(hdr, b, false)
+}
+
+func (p *PushPromise) unmarshalFrame(hdr *Header, b []byte, alias bool) error {
+	if hdr.StreamID == 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "push_promise: zero stream id"}
+	}
+
+	if hdr.Flags.Has(FlagPushPromisePadded) {
+		var ok bool
+		b, p.PadLength, ok = trimPadding(b, 4)
+		if !ok {
+			return ConnectionError{Code: ErrorCodeProtocol, Reason: "push_promise: invalid padding"}
+		}
+	}
+
+	if len(b) < 4 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "push_promise: invalid length"}
+	}
+
+	p.PromisedStreamID = uint31(b)
+	if p.PromisedStreamID == 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "push_promise: zero promised stream id"}
+	}
+
+	if hdr.Flags.Has(FlagPushPromiseEndHeaders) {
+		p.EndHeaders = true
+	}
+
+	p.Header = *hdr
+	p.Block = clonePayload(b[4:], alias)
+
+	return nil
+}
+
+const (
+	// FlagContinuationEndHeaders indicates a Continuation frame is the last
+	// of the header block.
+	// RFC 7540 Section 6.10
+	FlagContinuationEndHeaders = Flags(0x04)
+)
+
+// Continuation is used to continue a sequence of header block fragments
+// started by a Headers or PushPromise frame. Continuation frames MUST
+// immediately follow the frame they continue, with no other frames
+// interleaved on the connection, see HeaderBlockAssembler.
+// RFC 7540 Section 6.10
+type Continuation struct {
+	Header
+
+	// EndHeaders indicates this Continuation frame is the last of the header
+	// block.
+	EndHeaders bool
+
+	// Block contains an HPACK header block fragment, described in RFC 7541.
+	Block []byte
+}
+
+// MarshalFrame marshals Continuation into the wire format.
+func (c *Continuation) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(c, hdr)
+}
+
+// AppendFrame appends Continuation, including its Header, in the wire format to b.
+func (c *Continuation) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(c, b)
+}
+
+func (c *Continuation) appendPayload(b []byte) (Header, []byte) {
+	hdr := Header{Type: TypeContinuation, StreamID: c.Header.StreamID}
+
+	if c.EndHeaders {
+		hdr.Flags.Set(FlagContinuationEndHeaders)
+	}
+
+	return hdr, append(b, c.Block...)
+}
+
+// UnmarshalFrame unmarshals Continuation from the wire format.
+func (c *Continuation) UnmarshalFrame(hdr *Header, b []byte) error {
+	return c.unmarshalFrame(hdr, b, false)
+}
+
+func (c *Continuation) unmarshalFrame(hdr *Header, b []byte, alias bool) error {
+	if hdr.StreamID == 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: zero stream id"}
+	}
+
+	if hdr.Flags.Has(FlagContinuationEndHeaders) {
+		c.EndHeaders = true
+	}
+
+	c.Header = *hdr
+	c.Block = clonePayload(b, alias)
+
+	return nil
+}
+
+// appendPadding appends n bytes of zero padding to b.
+func appendPadding(b []byte, n uint8) []byte {
+	for i := uint8(0); i < n; i++ {
+		b = append(b, 0)
+	}
+
+	return b
+}
+
+// clonePayload returns a copy of b, or b itself if alias is true.
+func clonePayload(b []byte, alias bool) []byte {
+	if alias {
+		return b
+	}
+
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
+
+// trimPadding removes the Pad Length field and trailing padding from the
+// payload of a padded frame, returning false if the padding is longer than
+// the payload remaining after the Pad Length field and the fixed length of
+// any fields which follow it.
+// RFC 7540 Section 6.1
+func trimPadding(b []byte, fixed int) ([]byte, uint8, bool) {
+	if len(b) < 1 {
+		return nil, 0, false
+	}
+
+	pad := b[0]
+	if pad > 0 && int(pad) > len(b)-1-fixed {
+		return nil, 0, false
+	}
+
+	return b[1 : len(b)-int(pad)], pad, true
+}
+
+// appendUint31 appends v to b, clearing the reserved bit.
+func appendUint31(b []byte, v uint32) []byte {
+	n := len(b)
+	b = append(b, 0, 0, 0, 0)
+	putUint31(b[n:], v&(1<<31-1))
+
+	return b
+}
+
 func uint24(b []byte) uint32 {
 	_ = b[2] // bounds check hint to compiler; see golang.org/issue/14808
 	return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
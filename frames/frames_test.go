@@ -135,6 +135,61 @@ func TestSettingsUnmarshalFrame(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"Unknown",
+			&Header{Length: 12, Type: TypeSettings},
+			[]byte{0x0A, 0x0A, 0x12, 0x34, 0x56, 0x78, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00},
+			&Settings{
+				Header: Header{Length: 12, Type: TypeSettings},
+				Settings: []settings.Setting{
+					settings.Unknown{Identifier: 0x0A0A, Raw: 0x12345678},
+					settings.EnablePush{Enabled: false},
+				},
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 6, Type: TypeSettings, StreamID: 1},
+			[]byte{0x00, 0x03, 0x00, 0x00, 0x00, 0x64},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "settings: non-zero stream id"},
+		},
+		{
+			"AckPayload",
+			&Header{Length: 6, Type: TypeSettings, Flags: FlagSettingsAck},
+			[]byte{0x00, 0x03, 0x00, 0x00, 0x00, 0x64},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "settings: ack with payload"},
+		},
+		{
+			"Length",
+			&Header{Length: 5, Type: TypeSettings},
+			[]byte{0x00, 0x03, 0x00, 0x00, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "settings: invalid length"},
+		},
+		{
+			"EnablePush",
+			&Header{Length: 6, Type: TypeSettings},
+			[]byte{0x00, 0x02, 0x00, 0x00, 0x00, 0x02},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "settings: value must be 0 or 1"},
+		},
+		{
+			"InitialWindowSize",
+			&Header{Length: 6, Type: TypeSettings},
+			[]byte{0x00, 0x04, 0x80, 0x00, 0x00, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeFlowControl, Reason: "settings: initial window size too large"},
+		},
+		{
+			"MaxFrameSize",
+			&Header{Length: 6, Type: TypeSettings},
+			[]byte{0x00, 0x05, 0x00, 0x00, 0x3F, 0xFF},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "settings: max frame size out of range"},
+		},
 	}
 
 	for _, test := range tests {
@@ -180,6 +235,37 @@ func TestHeadersMarshalFrame(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"EndStream",
+			&Headers{Header: Header{StreamID: 1}, EndStream: true, EndHeaders: true, Block: []byte{0x82}},
+			&Header{Length: 1, Type: TypeHeaders, Flags: FlagHeadersEndStream | FlagHeadersEndHeaders, StreamID: 1},
+			[]byte{0x82},
+			nil,
+		},
+		{
+			"Priority",
+			&Headers{
+				Header:     Header{StreamID: 3},
+				EndHeaders: true,
+				Priority:   &PriorityParam{Exclusive: true, StreamDependency: 1, Weight: 255},
+				Block:      []byte{0x82},
+			},
+			&Header{Length: 6, Type: TypeHeaders, Flags: FlagHeadersEndHeaders | FlagHeadersPriority, StreamID: 3},
+			[]byte{0x80, 0x00, 0x00, 0x01, 0xFF, 0x82},
+			nil,
+		},
+		{
+			"Padded",
+			&Headers{
+				Header:    Header{StreamID: 3},
+				Priority:  &PriorityParam{StreamDependency: 1, Weight: 15},
+				PadLength: 2,
+				Block:     []byte{0x82},
+			},
+			&Header{Length: 9, Type: TypeHeaders, Flags: FlagHeadersPadded | FlagHeadersPriority, StreamID: 3},
+			[]byte{0x02, 0x00, 0x00, 0x00, 0x01, 0x0F, 0x82, 0x00, 0x00},
+			nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -212,7 +298,7 @@ func TestHeadersUnmarshalFrame(t *testing.T) {
 	}{
 		{
 			"nghttp2.org",
-			&Header{Length: 40, Type: TypeHeaders},
+			&Header{Length: 40, Type: TypeHeaders, StreamID: 1},
 			[]byte{
 				0x3f, 0xe1, 0x1f, 0x82, 0x04, 0x88, 0x62, 0x7b, 0x69, 0x1d,
 				0x48, 0x5d, 0x3e, 0x53, 0x86, 0x41, 0x88, 0xaa, 0x69, 0xd2,
@@ -220,7 +306,7 @@ func TestHeadersUnmarshalFrame(t *testing.T) {
 				0xc3, 0xab, 0xb8, 0x15, 0xc1, 0x53, 0x03, 0x2a, 0x2f, 0x2a,
 			},
 			&Headers{
-				Header: Header{Length: 40, Type: TypeHeaders},
+				Header: Header{Length: 40, Type: TypeHeaders, StreamID: 1},
 				Block: []byte{
 					0x3f, 0xe1, 0x1f, 0x82, 0x04, 0x88, 0x62, 0x7b, 0x69, 0x1d,
 					0x48, 0x5d, 0x3e, 0x53, 0x86, 0x41, 0x88, 0xaa, 0x69, 0xd2,
@@ -230,6 +316,65 @@ func TestHeadersUnmarshalFrame(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"Priority",
+			&Header{Length: 6, Type: TypeHeaders, Flags: FlagHeadersEndHeaders | FlagHeadersPriority, StreamID: 3},
+			[]byte{0x80, 0x00, 0x00, 0x01, 0xFF, 0x82},
+			&Headers{
+				Header:     Header{Length: 6, Type: TypeHeaders, Flags: FlagHeadersEndHeaders | FlagHeadersPriority, StreamID: 3},
+				EndHeaders: true,
+				Priority:   &PriorityParam{Exclusive: true, StreamDependency: 1, Weight: 255},
+				Block:      []byte{0x82},
+			},
+			nil,
+		},
+		{
+			"PriorityShort",
+			&Header{Length: 4, Type: TypeHeaders, Flags: FlagHeadersPriority, StreamID: 3},
+			[]byte{0x80, 0x00, 0x00, 0x01},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "headers: invalid length"},
+		},
+		{
+			"PrioritySelf",
+			&Header{Length: 5, Type: TypeHeaders, Flags: FlagHeadersPriority, StreamID: 3},
+			[]byte{0x00, 0x00, 0x00, 0x03, 0x0F},
+			nil,
+			StreamError{StreamID: 3, Code: ErrorCodeProtocol, Reason: "headers: stream depends on itself"},
+		},
+		{
+			"Padded",
+			&Header{Length: 9, Type: TypeHeaders, Flags: FlagHeadersPadded | FlagHeadersPriority, StreamID: 3},
+			[]byte{0x02, 0x00, 0x00, 0x00, 0x01, 0x0F, 0x82, 0x00, 0x00},
+			&Headers{
+				Header:    Header{Length: 9, Type: TypeHeaders, Flags: FlagHeadersPadded | FlagHeadersPriority, StreamID: 3},
+				Priority:  &PriorityParam{StreamDependency: 1, Weight: 15},
+				PadLength: 2,
+				Block:     []byte{0x82},
+			},
+			nil,
+		},
+		{
+			"PaddingTooLong",
+			&Header{Length: 3, Type: TypeHeaders, Flags: FlagHeadersPadded, StreamID: 3},
+			[]byte{0x03, 0x82, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "headers: invalid padding"},
+		},
+		{
+			"PaddingOverlapsPriority",
+			&Header{Length: 6, Type: TypeHeaders, Flags: FlagHeadersPadded | FlagHeadersPriority, StreamID: 3},
+			[]byte{0x05, 0x00, 0x00, 0x00, 0x01, 0x0F},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "headers: invalid padding"},
+		},
+		{
+			"ZeroStreamID",
+			&Header{Length: 1, Type: TypeHeaders, Flags: FlagHeadersEndHeaders},
+			[]byte{0x82},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "headers: zero stream id"},
+		},
 	}
 
 	for _, test := range tests {
@@ -265,6 +410,20 @@ func TestDatarMarshalFrame(t *testing.T) {
 			[]byte("User-agent: *\nDisallow: \n\nSitemap: //nghttp2.org/sitemap.xml \n"),
 			nil,
 		},
+		{
+			"Padded",
+			&Data{Header: Header{StreamID: 1}, EndStream: true, PadLength: 3, Data: []byte("hello")},
+			&Header{Length: 9, Type: TypeData, Flags: FlagDataEndStream | FlagDataPadded, StreamID: 1},
+			[]byte{0x03, 'h', 'e', 'l', 'l', 'o', 0x00, 0x00, 0x00},
+			nil,
+		},
+		{
+			"PaddedZero",
+			&Data{Header: Header{StreamID: 1, Flags: FlagDataPadded}, Data: []byte("hello")},
+			&Header{Length: 6, Type: TypeData, Flags: FlagDataPadded, StreamID: 1},
+			[]byte{0x00, 'h', 'e', 'l', 'l', 'o'},
+			nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -297,14 +456,58 @@ func TestDataUnmarshalFrame(t *testing.T) {
 	}{
 		{
 			"nghttp2.org",
-			&Header{Length: 62, Type: TypeData},
+			&Header{Length: 62, Type: TypeData, StreamID: 1},
 			[]byte("User-agent: *\nDisallow: \n\nSitemap: //nghttp2.org/sitemap.xml \n"),
 			&Data{
-				Header: Header{Length: 62, Type: TypeData},
+				Header: Header{Length: 62, Type: TypeData, StreamID: 1},
 				Data:   []byte("User-agent: *\nDisallow: \n\nSitemap: //nghttp2.org/sitemap.xml \n"),
 			},
 			nil,
 		},
+		{
+			"Padded",
+			&Header{Length: 9, Type: TypeData, Flags: FlagDataEndStream | FlagDataPadded, StreamID: 1},
+			[]byte{0x03, 'h', 'e', 'l', 'l', 'o', 0x00, 0x00, 0x00},
+			&Data{
+				Header:    Header{Length: 9, Type: TypeData, Flags: FlagDataEndStream | FlagDataPadded, StreamID: 1},
+				EndStream: true,
+				PadLength: 3,
+				Data:      []byte("hello"),
+			},
+			nil,
+		},
+		{
+			"PaddedEmpty",
+			&Header{Length: 4, Type: TypeData, Flags: FlagDataPadded, StreamID: 1},
+			[]byte{0x03, 0x00, 0x00, 0x00},
+			&Data{
+				Header:    Header{Length: 4, Type: TypeData, Flags: FlagDataPadded, StreamID: 1},
+				PadLength: 3,
+				Data:      []byte{},
+			},
+			nil,
+		},
+		{
+			"PaddingTooLong",
+			&Header{Length: 4, Type: TypeData, Flags: FlagDataPadded, StreamID: 1},
+			[]byte{0x04, 0x00, 0x00, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "data: invalid padding"},
+		},
+		{
+			"PaddingMissing",
+			&Header{Length: 0, Type: TypeData, Flags: FlagDataPadded, StreamID: 1},
+			[]byte{},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "data: invalid padding"},
+		},
+		{
+			"ZeroStreamID",
+			&Header{Length: 5, Type: TypeData},
+			[]byte("hello"),
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "data: zero stream id"},
+		},
 	}
 
 	for _, test := range tests {
@@ -324,3 +527,912 @@ func TestDataUnmarshalFrame(t *testing.T) {
 		})
 	}
 }
+
+func TestPingMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Ping   *Ping
+		Header *Header
+		Bytes  []byte
+		Error  error
+	}{
+		{
+			"Empty",
+			&Ping{},
+			&Header{Length: 8, Type: TypePing},
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			nil,
+		},
+		{
+			"Data",
+			&Ping{Data: [8]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}},
+			&Header{Length: 8, Type: TypePing},
+			[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			nil,
+		},
+		{
+			"Ack",
+			&Ping{Ack: true, Data: [8]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}},
+			&Header{Length: 8, Type: TypePing, Flags: FlagPingAck},
+			[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			nil,
+		},
+		{
+			"StreamID",
+			&Ping{Header: Header{StreamID: 1}},
+			&Header{Length: 8, Type: TypePing},
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.Ping.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPingUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Header *Header
+		Bytes  []byte
+		Ping   *Ping
+		Error  error
+	}{
+		{
+			"Data",
+			&Header{Length: 8, Type: TypePing},
+			[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			&Ping{
+				Header: Header{Length: 8, Type: TypePing},
+				Data:   [8]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			},
+			nil,
+		},
+		{
+			"Ack",
+			&Header{Length: 8, Type: TypePing, Flags: FlagPingAck},
+			[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			&Ping{
+				Header: Header{Length: 8, Type: TypePing, Flags: FlagPingAck},
+				Ack:    true,
+				Data:   [8]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 8, Type: TypePing, StreamID: 1},
+			[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "ping: non-zero stream id"},
+		},
+		{
+			"Short",
+			&Header{Length: 7, Type: TypePing},
+			[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "ping: invalid length"},
+		},
+		{
+			"Long",
+			&Header{Length: 9, Type: TypePing},
+			[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "ping: invalid length"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(Ping)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Ping, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGoAwayMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		GoAway *GoAway
+		Header *Header
+		Bytes  []byte
+		Error  error
+	}{
+		{
+			"NoError",
+			&GoAway{LastStreamID: 3},
+			&Header{Length: 8, Type: TypeGoAway},
+			[]byte{0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00},
+			nil,
+		},
+		{
+			"DebugData",
+			&GoAway{LastStreamID: 1, Code: ErrorCodeProtocol, DebugData: []byte("bye")},
+			&Header{Length: 11, Type: TypeGoAway},
+			[]byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 'b', 'y', 'e'},
+			nil,
+		},
+		{
+			"Reserved",
+			&GoAway{LastStreamID: 0xFFFFFFFF},
+			&Header{Length: 8, Type: TypeGoAway},
+			[]byte{0x7F, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.GoAway.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGoAwayUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Header *Header
+		Bytes  []byte
+		GoAway *GoAway
+		Error  error
+	}{
+		{
+			"NoError",
+			&Header{Length: 8, Type: TypeGoAway},
+			[]byte{0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00},
+			&GoAway{
+				Header:       Header{Length: 8, Type: TypeGoAway},
+				LastStreamID: 3,
+				DebugData:    []byte{},
+			},
+			nil,
+		},
+		{
+			"DebugData",
+			&Header{Length: 11, Type: TypeGoAway},
+			[]byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 'b', 'y', 'e'},
+			&GoAway{
+				Header:       Header{Length: 11, Type: TypeGoAway},
+				LastStreamID: 1,
+				Code:         ErrorCodeProtocol,
+				DebugData:    []byte("bye"),
+			},
+			nil,
+		},
+		{
+			"Reserved",
+			&Header{Length: 8, Type: TypeGoAway},
+			[]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00},
+			&GoAway{
+				Header:       Header{Length: 8, Type: TypeGoAway},
+				LastStreamID: 0x7FFFFFFF,
+				DebugData:    []byte{},
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 8, Type: TypeGoAway, StreamID: 1},
+			[]byte{0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "goaway: non-zero stream id"},
+		},
+		{
+			"Short",
+			&Header{Length: 7, Type: TypeGoAway},
+			[]byte{0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "goaway: invalid length"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(GoAway)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.GoAway, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestResetStreamMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name        string
+		ResetStream *ResetStream
+		Header      *Header
+		Bytes       []byte
+		Error       error
+	}{
+		{
+			"Cancel",
+			&ResetStream{Header: Header{StreamID: 1}, Code: ErrorCodeCancel},
+			&Header{Length: 4, Type: TypeResetStream, StreamID: 1},
+			[]byte{0x00, 0x00, 0x00, 0x08},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.ResetStream.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestResetStreamUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Header      *Header
+		Bytes       []byte
+		ResetStream *ResetStream
+		Error       error
+	}{
+		{
+			"Cancel",
+			&Header{Length: 4, Type: TypeResetStream, StreamID: 1},
+			[]byte{0x00, 0x00, 0x00, 0x08},
+			&ResetStream{
+				Header: Header{Length: 4, Type: TypeResetStream, StreamID: 1},
+				Code:   ErrorCodeCancel,
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 4, Type: TypeResetStream},
+			[]byte{0x00, 0x00, 0x00, 0x08},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "rst_stream: zero stream id"},
+		},
+		{
+			"Length",
+			&Header{Length: 5, Type: TypeResetStream, StreamID: 1},
+			[]byte{0x00, 0x00, 0x00, 0x08, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "rst_stream: invalid length"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(ResetStream)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.ResetStream, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWindowUpdateMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name         string
+		WindowUpdate *WindowUpdate
+		Header       *Header
+		Bytes        []byte
+		Error        error
+	}{
+		{
+			"Connection",
+			&WindowUpdate{Increment: 65535},
+			&Header{Length: 4, Type: TypeWindowUpdate},
+			[]byte{0x00, 0x00, 0xFF, 0xFF},
+			nil,
+		},
+		{
+			"Stream",
+			&WindowUpdate{Header: Header{StreamID: 3}, Increment: 1073741824},
+			&Header{Length: 4, Type: TypeWindowUpdate, StreamID: 3},
+			[]byte{0x40, 0x00, 0x00, 0x00},
+			nil,
+		},
+		{
+			"Reserved",
+			&WindowUpdate{Increment: 0xFFFFFFFF},
+			&Header{Length: 4, Type: TypeWindowUpdate},
+			[]byte{0x7F, 0xFF, 0xFF, 0xFF},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.WindowUpdate.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWindowUpdateUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Header       *Header
+		Bytes        []byte
+		WindowUpdate *WindowUpdate
+		Error        error
+	}{
+		{
+			"Connection",
+			&Header{Length: 4, Type: TypeWindowUpdate},
+			[]byte{0x00, 0x00, 0xFF, 0xFF},
+			&WindowUpdate{
+				Header:    Header{Length: 4, Type: TypeWindowUpdate},
+				Increment: 65535,
+			},
+			nil,
+		},
+		{
+			"Reserved",
+			&Header{Length: 4, Type: TypeWindowUpdate, StreamID: 3},
+			[]byte{0xC0, 0x00, 0x00, 0x00},
+			&WindowUpdate{
+				Header:    Header{Length: 4, Type: TypeWindowUpdate, StreamID: 3},
+				Increment: 1073741824,
+			},
+			nil,
+		},
+		{
+			"ZeroConnection",
+			&Header{Length: 4, Type: TypeWindowUpdate},
+			[]byte{0x00, 0x00, 0x00, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "window_update: zero increment"},
+		},
+		{
+			"ZeroStream",
+			&Header{Length: 4, Type: TypeWindowUpdate, StreamID: 3},
+			[]byte{0x80, 0x00, 0x00, 0x00},
+			nil,
+			StreamError{StreamID: 3, Code: ErrorCodeProtocol, Reason: "window_update: zero increment"},
+		},
+		{
+			"Length",
+			&Header{Length: 3, Type: TypeWindowUpdate, StreamID: 3},
+			[]byte{0x00, 0xFF, 0xFF},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "window_update: invalid length"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(WindowUpdate)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.WindowUpdate, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPriorityMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Priority *Priority
+		Header   *Header
+		Bytes    []byte
+		Error    error
+	}{
+		{
+			"Default",
+			&Priority{Header: Header{StreamID: 3}, PriorityParam: PriorityParam{Weight: 15}},
+			&Header{Length: 5, Type: TypePriority, StreamID: 3},
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x0F},
+			nil,
+		},
+		{
+			"Exclusive",
+			&Priority{Header: Header{StreamID: 5}, PriorityParam: PriorityParam{Exclusive: true, StreamDependency: 3, Weight: 200}},
+			&Header{Length: 5, Type: TypePriority, StreamID: 5},
+			[]byte{0x80, 0x00, 0x00, 0x03, 0xC8},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.Priority.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPriorityUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Header   *Header
+		Bytes    []byte
+		Priority *Priority
+		Error    error
+	}{
+		{
+			"Exclusive",
+			&Header{Length: 5, Type: TypePriority, StreamID: 5},
+			[]byte{0x80, 0x00, 0x00, 0x03, 0xC8},
+			&Priority{
+				Header:        Header{Length: 5, Type: TypePriority, StreamID: 5},
+				PriorityParam: PriorityParam{Exclusive: true, StreamDependency: 3, Weight: 200},
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 5, Type: TypePriority},
+			[]byte{0x80, 0x00, 0x00, 0x03, 0xC8},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "priority: zero stream id"},
+		},
+		{
+			"Length",
+			&Header{Length: 4, Type: TypePriority, StreamID: 5},
+			[]byte{0x80, 0x00, 0x00, 0x03},
+			nil,
+			StreamError{StreamID: 5, Code: ErrorCodeFrameSize, Reason: "priority: invalid length"},
+		},
+		{
+			"Self",
+			&Header{Length: 5, Type: TypePriority, StreamID: 5},
+			[]byte{0x00, 0x00, 0x00, 0x05, 0xC8},
+			nil,
+			StreamError{StreamID: 5, Code: ErrorCodeProtocol, Reason: "priority: stream depends on itself"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(Priority)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Priority, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPushPromiseMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name        string
+		PushPromise *PushPromise
+		Header      *Header
+		Bytes       []byte
+		Error       error
+	}{
+		{
+			"EndHeaders",
+			&PushPromise{Header: Header{StreamID: 1}, EndHeaders: true, PromisedStreamID: 2, Block: []byte{0x82, 0x87}},
+			&Header{Length: 6, Type: TypePushPromise, Flags: FlagPushPromiseEndHeaders, StreamID: 1},
+			[]byte{0x00, 0x00, 0x00, 0x02, 0x82, 0x87},
+			nil,
+		},
+		{
+			"Padded",
+			&PushPromise{Header: Header{StreamID: 1}, PadLength: 2, PromisedStreamID: 4, Block: []byte{0x82}},
+			&Header{Length: 8, Type: TypePushPromise, Flags: FlagPushPromisePadded, StreamID: 1},
+			[]byte{0x02, 0x00, 0x00, 0x00, 0x04, 0x82, 0x00, 0x00},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.PushPromise.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPushPromiseUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Header      *Header
+		Bytes       []byte
+		PushPromise *PushPromise
+		Error       error
+	}{
+		{
+			"EndHeaders",
+			&Header{Length: 6, Type: TypePushPromise, Flags: FlagPushPromiseEndHeaders, StreamID: 1},
+			[]byte{0x00, 0x00, 0x00, 0x02, 0x82, 0x87},
+			&PushPromise{
+				Header:           Header{Length: 6, Type: TypePushPromise, Flags: FlagPushPromiseEndHeaders, StreamID: 1},
+				EndHeaders:       true,
+				PromisedStreamID: 2,
+				Block:            []byte{0x82, 0x87},
+			},
+			nil,
+		},
+		{
+			"Padded",
+			&Header{Length: 8, Type: TypePushPromise, Flags: FlagPushPromisePadded, StreamID: 1},
+			[]byte{0x02, 0x80, 0x00, 0x00, 0x04, 0x82, 0x00, 0x00},
+			&PushPromise{
+				Header:           Header{Length: 8, Type: TypePushPromise, Flags: FlagPushPromisePadded, StreamID: 1},
+				PadLength:        2,
+				PromisedStreamID: 4,
+				Block:            []byte{0x82},
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 4, Type: TypePushPromise},
+			[]byte{0x00, 0x00, 0x00, 0x02},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "push_promise: zero stream id"},
+		},
+		{
+			"PromisedStreamID",
+			&Header{Length: 4, Type: TypePushPromise, StreamID: 1},
+			[]byte{0x00, 0x00, 0x00, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "push_promise: zero promised stream id"},
+		},
+		{
+			"Short",
+			&Header{Length: 3, Type: TypePushPromise, StreamID: 1},
+			[]byte{0x00, 0x00, 0x02},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "push_promise: invalid length"},
+		},
+		{
+			"PaddingTooLong",
+			&Header{Length: 6, Type: TypePushPromise, Flags: FlagPushPromisePadded, StreamID: 1},
+			[]byte{0x06, 0x00, 0x00, 0x00, 0x02, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "push_promise: invalid padding"},
+		},
+		{
+			"PaddingOverlapsPromisedStreamID",
+			&Header{Length: 6, Type: TypePushPromise, Flags: FlagPushPromisePadded, StreamID: 1},
+			[]byte{0x02, 0x00, 0x00, 0x00, 0x02, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "push_promise: invalid padding"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(PushPromise)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.PushPromise, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestContinuationMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Continuation *Continuation
+		Header       *Header
+		Bytes        []byte
+		Error        error
+	}{
+		{
+			"EndHeaders",
+			&Continuation{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x82, 0x87}},
+			&Header{Length: 2, Type: TypeContinuation, Flags: FlagContinuationEndHeaders, StreamID: 1},
+			[]byte{0x82, 0x87},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.Continuation.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestContinuationUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Header       *Header
+		Bytes        []byte
+		Continuation *Continuation
+		Error        error
+	}{
+		{
+			"EndHeaders",
+			&Header{Length: 2, Type: TypeContinuation, Flags: FlagContinuationEndHeaders, StreamID: 1},
+			[]byte{0x82, 0x87},
+			&Continuation{
+				Header:     Header{Length: 2, Type: TypeContinuation, Flags: FlagContinuationEndHeaders, StreamID: 1},
+				EndHeaders: true,
+				Block:      []byte{0x82, 0x87},
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 2, Type: TypeContinuation},
+			[]byte{0x82, 0x87},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: zero stream id"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(Continuation)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Continuation, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+// marshalOnly is a Frame that does not implement Appender.
+type marshalOnly struct {
+	Ping
+}
+
+func (m *marshalOnly) MarshalFrame(hdr *Header) ([]byte, error) {
+	return m.Ping.MarshalFrame(hdr)
+}
+
+func TestAppendFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Source []byte
+		Frame  Frame
+		Bytes  []byte
+		Error  error
+	}{
+		{
+			"Settings",
+			nil,
+			&Settings{Ack: true},
+			[]byte{0x00, 0x00, 0x00, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00},
+			nil,
+		},
+		{
+			"Headers",
+			nil,
+			&Headers{
+				Header:     Header{StreamID: 3},
+				EndHeaders: true,
+				Priority:   &PriorityParam{Exclusive: true, StreamDependency: 1, Weight: 255},
+				PadLength:  1,
+				Block:      []byte{0x82},
+			},
+			[]byte{0x00, 0x00, 0x08, 0x01, 0x2C, 0x00, 0x00, 0x00, 0x03, 0x01, 0x80, 0x00, 0x00, 0x01, 0xFF, 0x82, 0x00},
+			nil,
+		},
+		{
+			"Append",
+			[]byte{0xAA},
+			&WindowUpdate{Header: Header{StreamID: 1}, Increment: 1},
+			[]byte{0xAA, 0x00, 0x00, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01},
+			nil,
+		},
+		{
+			"MarshalOnly",
+			[]byte{0xAA},
+			&marshalOnly{Ping{Ack: true}},
+			[]byte{0xAA, 0x00, 0x00, 0x08, 0x06, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			nil,
+		},
+		{
+			"TooBig",
+			[]byte{0xAA},
+			&Data{Header: Header{StreamID: 1}, Data: make([]byte, 1<<24)},
+			[]byte{0xAA},
+			ErrFrameTooBig,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			bytes, err := AppendFrame(test.Source, test.Frame)
+
+			if test.Error == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, test.Error, err)
+			}
+
+			assert.Equal(t, test.Bytes, bytes)
+		})
+	}
+}
+
+func TestMarshalFrameReuseHeader(t *testing.T) {
+	hdr := new(Header)
+
+	_, err := (&Data{Header: Header{StreamID: 1}, EndStream: true}).MarshalFrame(hdr)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = (&Ping{}).MarshalFrame(hdr)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Header{Length: 8, Type: TypePing}, hdr)
+	}
+
+	_, err = (&Settings{}).MarshalFrame(hdr)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Header{Type: TypeSettings}, hdr)
+	}
+}
+
+func TestAppendFrameMarshalFrame(t *testing.T) {
+	frames := []Frame{
+		&Data{Header: Header{StreamID: 1}, EndStream: true, PadLength: 2, Data: []byte("hello")},
+		&Headers{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x82}},
+		&Priority{Header: Header{StreamID: 3}, PriorityParam: PriorityParam{StreamDependency: 1, Weight: 15}},
+		&ResetStream{Header: Header{StreamID: 1}, Code: ErrorCodeCancel},
+		&Settings{Settings: []settings.Setting{settings.EnablePush{Enabled: false}}},
+		&PushPromise{Header: Header{StreamID: 1}, PromisedStreamID: 2, Block: []byte{0x82}},
+		&Ping{Data: [8]byte{0x01}},
+		&GoAway{LastStreamID: 1, Code: ErrorCodeProtocol, DebugData: []byte("bye")},
+		&WindowUpdate{Increment: 65535},
+		&Continuation{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x86}},
+		&UnknownFrame{Header: Header{Type: Type(0xFF), StreamID: 1}, Payload: []byte{0xAA}},
+	}
+
+	for _, frame := range frames {
+		hdr := new(Header)
+		payload, err := frame.MarshalFrame(hdr)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		header, err := hdr.MarshalFrameHeader()
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		bytes, err := frame.(Appender).AppendFrame(nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, append(header, payload...), bytes)
+		}
+	}
+}
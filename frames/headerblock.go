@@ -0,0 +1,117 @@
+package frames
+
+// DefaultMaxHeaderBlockSize is the maximum size of a reassembled header block
+// used by HeaderBlockAssembler when MaxSize is not set, in bytes.
+const DefaultMaxHeaderBlockSize = 1 << 20
+
+// HeaderBlockAssembler reassembles a complete header block from a Headers or
+// PushPromise frame followed by zero or more Continuation frames. It should
+// be given every frame read from a connection, in order.
+// RFC 7540 Section 4.3
+type HeaderBlockAssembler struct {
+	// MaxSize is the maximum size of a reassembled header block, in bytes.
+	// If zero, DefaultMaxHeaderBlockSize is used.
+	MaxSize int
+
+	frame    Frame
+	streamID uint32
+	block    []byte
+}
+
+// Assemble processes the next Frame read from a connection. Frames unrelated
+// to a header block are returned unmodified. A Headers or PushPromise frame
+// is withheld until its header block is complete, at which point it is
+// returned with EndHeaders set and Block containing the entire header block.
+// While a header block is incomplete, nil is returned.
+//
+// A ConnectionError of type ErrorCodeProtocol is returned if any frame other
+// than a Continuation frame for the same Stream interleaves a header block,
+// if a Continuation frame is received outside of a header block, or if the
+// header block exceeds MaxSize.
+func (a *HeaderBlockAssembler) Assemble(f Frame) (Frame, error) {
+	if a.frame == nil {
+		switch f := f.(type) {
+		case *Headers:
+			if f.EndHeaders {
+				return f, nil
+			}
+
+			return nil, a.start(f, f.StreamID, f.Block)
+
+		case *PushPromise:
+			if f.EndHeaders {
+				return f, nil
+			}
+
+			return nil, a.start(f, f.StreamID, f.Block)
+
+		case *Continuation:
+			return nil, ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: unexpected frame"}
+
+		default:
+			return f, nil
+		}
+	}
+
+	c, ok := f.(*Continuation)
+	if !ok {
+		return nil, ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: header block interrupted"}
+	} else if c.StreamID != a.streamID {
+		return nil, ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: stream id mismatch"}
+	}
+
+	if err := a.append(c.Block); err != nil {
+		return nil, err
+	}
+
+	if !c.EndHeaders {
+		return nil, nil
+	}
+
+	frame := a.frame
+
+	switch frame := frame.(type) {
+	case *Headers:
+		frame.EndHeaders = true
+		frame.Block = a.block
+
+	case *PushPromise:
+		frame.EndHeaders = true
+		frame.Block = a.block
+	}
+
+	a.Reset()
+
+	return frame, nil
+}
+
+// Reset discards any partially assembled header block.
+func (a *HeaderBlockAssembler) Reset() {
+	a.frame = nil
+	a.streamID = 0
+	a.block = nil
+}
+
+func (a *HeaderBlockAssembler) start(f Frame, streamID uint32, block []byte) error {
+	a.frame = f
+	a.streamID = streamID
+	a.block = nil
+
+	return a.append(block)
+}
+
+func (a *HeaderBlockAssembler) append(block []byte) error {
+	limit := a.MaxSize
+	if limit == 0 {
+		limit = DefaultMaxHeaderBlockSize
+	}
+
+	if len(a.block)+len(block) > limit {
+		a.Reset()
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: header block too large"}
+	}
+
+	a.block = append(a.block, block...)
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package frames
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderBlockAssemblerAssemble(t *testing.T) {
+	tests := []struct {
+		Name    string
+		MaxSize int
+		Frames  []Frame
+		Result  Frame
+		Error   error
+	}{
+		{
+			"Unrelated",
+			0,
+			[]Frame{&Ping{}},
+			&Ping{},
+			nil,
+		},
+		{
+			"EndHeaders",
+			0,
+			[]Frame{&Headers{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x82}}},
+			&Headers{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x82}},
+			nil,
+		},
+		{
+			"Headers",
+			0,
+			[]Frame{
+				&Headers{Header: Header{StreamID: 1}, EndStream: true, Block: []byte{0x82}},
+				&Continuation{Header: Header{StreamID: 1}, Block: []byte{0x86}},
+				&Continuation{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x84}},
+			},
+			&Headers{Header: Header{StreamID: 1}, EndStream: true, EndHeaders: true, Block: []byte{0x82, 0x86, 0x84}},
+			nil,
+		},
+		{
+			"PushPromise",
+			0,
+			[]Frame{
+				&PushPromise{Header: Header{StreamID: 1}, PromisedStreamID: 2, Block: []byte{0x82}},
+				&Continuation{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x86}},
+			},
+			&PushPromise{Header: Header{StreamID: 1}, EndHeaders: true, PromisedStreamID: 2, Block: []byte{0x82, 0x86}},
+			nil,
+		},
+		{
+			"Unexpected",
+			0,
+			[]Frame{&Continuation{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x86}}},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: unexpected frame"},
+		},
+		{
+			"Interrupted",
+			0,
+			[]Frame{
+				&Headers{Header: Header{StreamID: 1}, Block: []byte{0x82}},
+				&Ping{},
+			},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: header block interrupted"},
+		},
+		{
+			"StreamMismatch",
+			0,
+			[]Frame{
+				&Headers{Header: Header{StreamID: 1}, Block: []byte{0x82}},
+				&Continuation{Header: Header{StreamID: 3}, EndHeaders: true, Block: []byte{0x86}},
+			},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: stream id mismatch"},
+		},
+		{
+			"TooLarge",
+			2,
+			[]Frame{
+				&Headers{Header: Header{StreamID: 1}, Block: []byte{0x82, 0x86}},
+				&Continuation{Header: Header{StreamID: 1}, EndHeaders: true, Block: []byte{0x84}},
+			},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "continuation: header block too large"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assembler := &HeaderBlockAssembler{MaxSize: test.MaxSize}
+
+			var result Frame
+			var err error
+
+			for _, frame := range test.Frames {
+				result, err = assembler.Assemble(frame)
+				if err != nil {
+					break
+				}
+			}
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Result, result)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, result)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
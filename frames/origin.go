@@ -0,0 +1,104 @@
+package frames
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	// ErrOriginStream is returned when attempting to marshal an Origin frame
+	// on a Stream other than zero.
+	ErrOriginStream = errors.New("frames: origin on non-zero stream")
+
+	// ErrOriginInvalid is returned when attempting to marshal an Origin frame
+	// containing an origin that is not ASCII or is too long.
+	ErrOriginInvalid = errors.New("frames: origin invalid")
+)
+
+// Origin indicates the set of origins a server is authoritative for on the
+// connection, allowing clients to control connection coalescing. It is only
+// valid on Stream zero.
+// RFC 8336 Section 2
+type Origin struct {
+	Header
+
+	// Origins are the ASCII serialization of each origin, as defined in
+	// RFC 6454 Section 6.2.
+	Origins []string
+}
+
+// MarshalFrame marshals Origin into the wire format.
+func (o *Origin) MarshalFrame(hdr *Header) ([]byte, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	return marshalFrame(o, hdr)
+}
+
+// AppendFrame appends Origin, including its Header, in the wire format to b.
+func (o *Origin) AppendFrame(b []byte) ([]byte, error) {
+	if err := o.validate(); err != nil {
+		return b, err
+	}
+
+	return appendFrame(o, b)
+}
+
+func (o *Origin) appendPayload(b []byte) (Header, []byte) {
+	for _, origin := range o.Origins {
+		b = binary.BigEndian.AppendUint16(b, uint16(len(origin)))
+		b = append(b, origin...)
+	}
+
+	return Header{Type: TypeOrigin}, b
+}
+
+func (o *Origin) validate() error {
+	if o.Header.StreamID != 0 {
+		return ErrOriginStream
+	}
+
+	for _, origin := range o.Origins {
+		if len(origin) > 0xFFFF {
+			return ErrOriginInvalid
+		}
+
+		for i := 0; i < len(origin); i++ {
+			if origin[i] > 0x7F {
+				return ErrOriginInvalid
+			}
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalFrame unmarshals Origin from the wire format. ErrIgnoreFrame is
+// returned if the Origin frame is not on Stream zero.
+func (o *Origin) UnmarshalFrame(hdr *Header, b []byte) error {
+	if hdr.StreamID != 0 {
+		return ErrIgnoreFrame
+	}
+
+	var origins []string
+
+	for len(b) > 0 {
+		if len(b) < 2 {
+			return ConnectionError{Code: ErrorCodeFrameSize, Reason: "origin: invalid length"}
+		}
+
+		n := int(binary.BigEndian.Uint16(b))
+		if n > len(b)-2 {
+			return ConnectionError{Code: ErrorCodeFrameSize, Reason: "origin: invalid origin length"}
+		}
+
+		origins = append(origins, string(b[2:2+n]))
+		b = b[2+n:]
+	}
+
+	o.Header = *hdr
+	o.Origins = origins
+
+	return nil
+}
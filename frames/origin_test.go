@@ -0,0 +1,131 @@
+package frames
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOriginMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Origin *Origin
+		Header *Header
+		Bytes  []byte
+		Error  error
+	}{
+		{
+			"Empty",
+			&Origin{},
+			&Header{Type: TypeOrigin},
+			[]byte{},
+			nil,
+		},
+		{
+			"Origins",
+			&Origin{Origins: []string{"https://a.b", "https://c.d"}},
+			&Header{Length: 26, Type: TypeOrigin},
+			append(append([]byte{0x00, 0x0B}, "https://a.b"...), append([]byte{0x00, 0x0B}, "https://c.d"...)...),
+			nil,
+		},
+		{
+			"StreamID",
+			&Origin{Header: Header{StreamID: 1}, Origins: []string{"https://a.b"}},
+			nil,
+			nil,
+			ErrOriginStream,
+		},
+		{
+			"NonASCII",
+			&Origin{Origins: []string{"https://ä.b"}},
+			nil,
+			nil,
+			ErrOriginInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.Origin.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestOriginUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Header *Header
+		Bytes  []byte
+		Origin *Origin
+		Error  error
+	}{
+		{
+			"Empty",
+			&Header{Type: TypeOrigin},
+			[]byte{},
+			&Origin{Header: Header{Type: TypeOrigin}},
+			nil,
+		},
+		{
+			"Origins",
+			&Header{Length: 26, Type: TypeOrigin},
+			append(append([]byte{0x00, 0x0B}, "https://a.b"...), append([]byte{0x00, 0x0B}, "https://c.d"...)...),
+			&Origin{
+				Header:  Header{Length: 26, Type: TypeOrigin},
+				Origins: []string{"https://a.b", "https://c.d"},
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 13, Type: TypeOrigin, StreamID: 1},
+			append([]byte{0x00, 0x0B}, "https://a.b"...),
+			nil,
+			ErrIgnoreFrame,
+		},
+		{
+			"Short",
+			&Header{Length: 1, Type: TypeOrigin},
+			[]byte{0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "origin: invalid length"},
+		},
+		{
+			"OriginLength",
+			&Header{Length: 3, Type: TypeOrigin},
+			[]byte{0x00, 0x02, 'a'},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "origin: invalid origin length"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(Origin)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Origin, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
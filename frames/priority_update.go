@@ -0,0 +1,326 @@
+package frames
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrPriorityFieldValue is returned when parsing a Priority Field Value that
+// is not a valid Structured Fields Dictionary.
+var ErrPriorityFieldValue = errors.New("frames: invalid priority field value")
+
+// PriorityUpdate signals a change to the priority of a Stream, replacing the
+// deprecated Priority frame and Headers priority fields. It is only valid on
+// Stream zero.
+// RFC 9218 Section 7.1
+type PriorityUpdate struct {
+	Header
+
+	// PrioritizedStreamID is the Stream whose priority is being updated.
+	PrioritizedStreamID uint32
+
+	// FieldValue is the Priority Field Value in ASCII text, it can be parsed
+	// with ParseExtensiblePriority.
+	FieldValue string
+}
+
+// MarshalFrame marshals PriorityUpdate into the wire format.
+func (p *PriorityUpdate) MarshalFrame(hdr *Header) ([]byte, error) {
+	return marshalFrame(p, hdr)
+}
+
+// AppendFrame appends PriorityUpdate, including its Header, in the wire
+// format to b.
+func (p *PriorityUpdate) AppendFrame(b []byte) ([]byte, error) {
+	return appendFrame(p, b)
+}
+
+func (p *PriorityUpdate) appendPayload(b []byte) (Header, []byte) {
+	b = appendUint31(b, p.PrioritizedStreamID)
+	b = append(b, p.FieldValue...)
+
+	return Header{Type: TypePriorityUpdate}, b
+}
+
+// UnmarshalFrame unmarshals PriorityUpdate from the wire format.
+func (p *PriorityUpdate) UnmarshalFrame(hdr *Header, b []byte) error {
+	if hdr.StreamID != 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "priority_update: non-zero stream id"}
+	} else if len(b) < 4 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "priority_update: invalid length"}
+	}
+
+	id := uint31(b)
+	if id == 0 {
+		return ConnectionError{Code: ErrorCodeProtocol, Reason: "priority_update: zero prioritized stream id"}
+	}
+
+	p.Header = *hdr
+	p.PrioritizedStreamID = id
+	p.FieldValue = string(b[4:])
+
+	return nil
+}
+
+// DefaultUrgency is the urgency of a Stream without an explicit urgency.
+// RFC 9218 Section 4.1
+const DefaultUrgency = 3
+
+// ExtensiblePriority contains the priority parameters of a Stream, carried
+// in a Priority Field Value by PriorityUpdate frames and the Priority header
+// field.
+// RFC 9218 Section 4
+type ExtensiblePriority struct {
+	// Urgency is the urgency of the Stream from 0 to 7, where lower values
+	// are more urgent.
+	Urgency uint8
+
+	// Incremental indicates the Stream can be processed incrementally,
+	// allowing bandwidth to be shared with Streams of the same Urgency.
+	Incremental bool
+}
+
+// String returns the Priority Field Value of p, omitting default values.
+func (p ExtensiblePriority) String() string {
+	var members []string
+
+	if p.Urgency != DefaultUrgency {
+		members = append(members, "u="+strconv.Itoa(int(p.Urgency)))
+	}
+	if p.Incremental {
+		members = append(members, "i")
+	}
+
+	return strings.Join(members, ", ")
+}
+
+// ParseExtensiblePriority parses the urgency (u) and incremental (i)
+// parameters from a Priority Field Value, a Structured Fields Dictionary.
+// Unknown parameters, parameters with an unexpected type and urgencies out of
+// range are ignored. If s is not a valid Dictionary, the default priority is
+// returned along with ErrPriorityFieldValue.
+// RFC 9218 Section 4, RFC 8941 Section 3.2
+func ParseExtensiblePriority(s string) (ExtensiblePriority, error) {
+	p := ExtensiblePriority{Urgency: DefaultUrgency}
+
+	members, ok := parseDictionary(s)
+	if !ok {
+		return p, ErrPriorityFieldValue
+	}
+
+	for _, member := range members {
+		switch member.key {
+		case "u":
+			if v, ok := member.value.(int64); ok && v >= 0 && v <= 7 {
+				p.Urgency = uint8(v)
+			}
+
+		case "i":
+			if v, ok := member.value.(bool); ok {
+				p.Incremental = v
+			}
+		}
+	}
+
+	return p, nil
+}
+
+type sfMember struct {
+	key   string
+	value interface{}
+}
+
+// parseDictionary parses a Structured Fields Dictionary, returning each
+// member in order. Parameters are validated but discarded, Inner Lists are
+// not supported.
+// RFC 8941 Section 4.2.2
+func parseDictionary(s string) ([]sfMember, bool) {
+	var members []sfMember
+
+	s = strings.Trim(s, " \t")
+
+	for len(s) > 0 {
+		key, rest, ok := parseKey(s)
+		if !ok {
+			return nil, false
+		}
+
+		var value interface{} = true
+
+		if len(rest) > 0 && rest[0] == '=' {
+			value, rest, ok = parseBareItem(rest[1:])
+			if !ok {
+				return nil, false
+			}
+		}
+
+		rest, ok = skipParameters(rest)
+		if !ok {
+			return nil, false
+		}
+
+		members = append(members, sfMember{key: key, value: value})
+
+		s = strings.TrimLeft(rest, " \t")
+		if len(s) == 0 {
+			break
+		} else if s[0] != ',' {
+			return nil, false
+		}
+
+		s = strings.TrimLeft(s[1:], " \t")
+		if len(s) == 0 {
+			return nil, false
+		}
+	}
+
+	return members, true
+}
+
+// parseKey parses a Structured Fields Key.
+// RFC 8941 Section 4.2.3.3
+func parseKey(s string) (string, string, bool) {
+	if len(s) == 0 || !(s[0] == '*' || (s[0] >= 'a' && s[0] <= 'z')) {
+		return "", s, false
+	}
+
+	i := 1
+	for i < len(s) && isKeyChar(s[i]) {
+		i++
+	}
+
+	return s[:i], s[i:], true
+}
+
+func isKeyChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' || c == '-' || c == '.' || c == '*'
+}
+
+// skipParameters parses and discards Structured Fields Parameters.
+// RFC 8941 Section 4.2.3.2
+func skipParameters(s string) (string, bool) {
+	for len(s) > 0 && s[0] == ';' {
+		var ok bool
+
+		_, s, ok = parseKey(strings.TrimLeft(s[1:], " "))
+		if !ok {
+			return s, false
+		}
+
+		if len(s) > 0 && s[0] == '=' {
+			_, s, ok = parseBareItem(s[1:])
+			if !ok {
+				return s, false
+			}
+		}
+	}
+
+	return s, true
+}
+
+// parseBareItem parses a Structured Fields Bare Item. Integers are returned
+// as int64, Booleans as bool and all other types as string.
+// RFC 8941 Section 4.2.3.1
+func parseBareItem(s string) (interface{}, string, bool) {
+	if len(s) == 0 {
+		return nil, s, false
+	}
+
+	switch c := s[0]; {
+	case c == '-' || (c >= '0' && c <= '9'):
+		i := 0
+		if c == '-' {
+			i++
+		}
+
+		start := i
+		for i < len(s) && ((s[i] >= '0' && s[i] <= '9') || s[i] == '.') {
+			i++
+		}
+
+		if i == start {
+			return nil, s, false
+		}
+
+		if strings.IndexByte(s[start:i], '.') >= 0 {
+			if _, err := strconv.ParseFloat(s[:i], 64); err != nil {
+				return nil, s, false
+			}
+
+			return s[:i], s[i:], true
+		}
+
+		if i-start > 15 {
+			return nil, s, false
+		}
+
+		v, err := strconv.ParseInt(s[:i], 10, 64)
+		if err != nil {
+			return nil, s, false
+		}
+
+		return v, s[i:], true
+
+	case c == '?':
+		if len(s) < 2 || (s[1] != '0' && s[1] != '1') {
+			return nil, s, false
+		}
+
+		return s[1] == '1', s[2:], true
+
+	case c == '"':
+		var b strings.Builder
+
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+				if i >= len(s) || (s[i] != '"' && s[i] != '\\') {
+					return nil, s, false
+				}
+
+				b.WriteByte(s[i])
+
+			case '"':
+				return b.String(), s[i+1:], true
+
+			default:
+				if s[i] < 0x20 || s[i] > 0x7E {
+					return nil, s, false
+				}
+
+				b.WriteByte(s[i])
+			}
+		}
+
+		return nil, s, false
+
+	case c == ':':
+		i := strings.IndexByte(s[1:], ':')
+		if i < 0 {
+			return nil, s, false
+		}
+
+		return s[1 : i+1], s[i+2:], true
+
+	case c == '*' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		i := 1
+		for i < len(s) && isTokenChar(s[i]) {
+			i++
+		}
+
+		return s[:i], s[i:], true
+
+	default:
+		return nil, s, false
+	}
+}
+
+func isTokenChar(c byte) bool {
+	if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+		return true
+	}
+
+	return strings.IndexByte("!#$%&'*+-.^_`|~:/", c) >= 0
+}
@@ -0,0 +1,157 @@
+package frames
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityUpdateMarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name           string
+		PriorityUpdate *PriorityUpdate
+		Header         *Header
+		Bytes          []byte
+		Error          error
+	}{
+		{
+			"Urgency",
+			&PriorityUpdate{PrioritizedStreamID: 3, FieldValue: "u=1, i"},
+			&Header{Length: 10, Type: TypePriorityUpdate},
+			append([]byte{0x00, 0x00, 0x00, 0x03}, "u=1, i"...),
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hdr := new(Header)
+			bytes, err := test.PriorityUpdate.MarshalFrame(hdr)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Header, hdr)
+					assert.Equal(t, test.Bytes, bytes)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, bytes)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPriorityUpdateUnmarshalFrame(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Header         *Header
+		Bytes          []byte
+		PriorityUpdate *PriorityUpdate
+		Error          error
+	}{
+		{
+			"Urgency",
+			&Header{Length: 10, Type: TypePriorityUpdate},
+			append([]byte{0x80, 0x00, 0x00, 0x03}, "u=1, i"...),
+			&PriorityUpdate{
+				Header:              Header{Length: 10, Type: TypePriorityUpdate},
+				PrioritizedStreamID: 3,
+				FieldValue:          "u=1, i",
+			},
+			nil,
+		},
+		{
+			"StreamID",
+			&Header{Length: 4, Type: TypePriorityUpdate, StreamID: 3},
+			[]byte{0x00, 0x00, 0x00, 0x03},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "priority_update: non-zero stream id"},
+		},
+		{
+			"Short",
+			&Header{Length: 3, Type: TypePriorityUpdate},
+			[]byte{0x00, 0x00, 0x03},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "priority_update: invalid length"},
+		},
+		{
+			"PrioritizedStreamID",
+			&Header{Length: 4, Type: TypePriorityUpdate},
+			[]byte{0x00, 0x00, 0x00, 0x00},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "priority_update: zero prioritized stream id"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			frame := new(PriorityUpdate)
+			err := frame.UnmarshalFrame(test.Header, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.PriorityUpdate, frame)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseExtensiblePriority(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Value    string
+		Priority ExtensiblePriority
+		Error    error
+	}{
+		{"Empty", "", ExtensiblePriority{Urgency: 3}, nil},
+		{"Urgency", "u=5", ExtensiblePriority{Urgency: 5}, nil},
+		{"Incremental", "i", ExtensiblePriority{Urgency: 3, Incremental: true}, nil},
+		{"IncrementalFalse", "i=?0", ExtensiblePriority{Urgency: 3}, nil},
+		{"Both", "u=0, i=?1", ExtensiblePriority{Urgency: 0, Incremental: true}, nil},
+		{"Whitespace", "  u=1 ,\ti  ", ExtensiblePriority{Urgency: 1, Incremental: true}, nil},
+		{"LastWins", "u=1, u=6", ExtensiblePriority{Urgency: 6}, nil},
+		{"OutOfRange", "u=8", ExtensiblePriority{Urgency: 3}, nil},
+		{"WrongType", `u="1", i=1`, ExtensiblePriority{Urgency: 3}, nil},
+		{"Unknown", `u=2, foo="a,b", bar=:aGk=:, baz=tok/en, q=0.5`, ExtensiblePriority{Urgency: 2}, nil},
+		{"Parameters", "u=2;x=1;y, i;z", ExtensiblePriority{Urgency: 2, Incremental: true}, nil},
+		{"TrailingComma", "u=2,", ExtensiblePriority{Urgency: 3}, ErrPriorityFieldValue},
+		{"InvalidKey", "U=2", ExtensiblePriority{Urgency: 3}, ErrPriorityFieldValue},
+		{"InvalidValue", "u=", ExtensiblePriority{Urgency: 3}, ErrPriorityFieldValue},
+		{"InvalidString", `u="unterminated`, ExtensiblePriority{Urgency: 3}, ErrPriorityFieldValue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			priority, err := ParseExtensiblePriority(test.Value)
+
+			assert.Equal(t, test.Error, err)
+			assert.Equal(t, test.Priority, priority)
+		})
+	}
+}
+
+func TestExtensiblePriorityString(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Priority ExtensiblePriority
+		Value    string
+	}{
+		{"Default", ExtensiblePriority{Urgency: 3}, ""},
+		{"Urgency", ExtensiblePriority{Urgency: 1}, "u=1"},
+		{"Incremental", ExtensiblePriority{Urgency: 3, Incremental: true}, "i"},
+		{"Both", ExtensiblePriority{Urgency: 0, Incremental: true}, "u=0, i"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Value, test.Priority.String())
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package frames
+
+import (
+	"io"
+
+	"github.com/jamescun/http2/settings"
+)
+
+// DefaultMaxFrameSize is the initial value of settings.MaxFrameSize, the
+// largest frame payload a peer may send before any Settings are exchanged.
+// RFC 7540 Section 6.5.2
+const DefaultMaxFrameSize = 1 << 14
+
+// Reader reads Frames from an underlying io.Reader, such as a net.Conn.
+type Reader struct {
+	// MaxFrameSize is the largest frame payload accepted by Reader, it
+	// should be the value most recently advertised to and acknowledged by
+	// the peer.
+	MaxFrameSize settings.MaxFrameSize
+
+	// AliasPayloads enables zero-allocation decoding. If true, Frames
+	// returned by ReadFrame, and their payloads, are reused by Reader and
+	// are only valid until the next call to ReadFrame. Frames that must be
+	// retained should be copied by the caller.
+	AliasPayloads bool
+
+	r      io.Reader
+	hdr    [HeaderLength]byte
+	header Header
+	buf    []byte
+	cache  frameCache
+}
+
+// NewReader returns a Reader reading from r, accepting frame payloads up to
+// DefaultMaxFrameSize.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		MaxFrameSize: settings.MaxFrameSize{Size: DefaultMaxFrameSize},
+		r:            r,
+	}
+}
+
+// ReadFrame reads the next Frame. Frames with a Type not understood by this
+// package, and not registered with RegisterFrame, are returned as an
+// UnknownFrame, which receivers MUST ignore.
+//
+// A ConnectionError or StreamError is returned if the Frame is invalid, the
+// offending Frame is consumed in its entirety and further Frames may be read
+// following a StreamError or ErrIgnoreFrame. Errors from the underlying
+// io.Reader are returned as-is, io.EOF is only returned if no bytes of the
+// next Frame were read.
+// RFC 7540 Section 4.1
+func (r *Reader) ReadFrame() (Frame, error) {
+	if _, err := io.ReadFull(r.r, r.hdr[:]); err != nil {
+		return nil, err
+	}
+
+	hdr := &r.header
+	if err := hdr.UnmarshalFrameHeader(r.hdr[:]); err != nil {
+		return nil, err
+	}
+
+	if hdr.Length > r.MaxFrameSize.Size {
+		return nil, r.discard(hdr)
+	}
+
+	if cap(r.buf) < int(hdr.Length) {
+		r.buf = make([]byte, hdr.Length)
+	}
+	b := r.buf[:hdr.Length]
+
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	if r.AliasPayloads {
+		frame := r.cache.get(hdr.Type)
+
+		if f, ok := frame.(aliasingFrame); ok {
+			if err := f.unmarshalFrame(hdr, b, true); err != nil {
+				return nil, err
+			}
+
+			return frame, nil
+		}
+
+		if err := frame.UnmarshalFrame(hdr, b); err != nil {
+			return nil, err
+		}
+
+		return frame, nil
+	}
+
+	frame := newFrame(hdr.Type)
+
+	if err := frame.UnmarshalFrame(hdr, b); err != nil {
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+// aliasingFrame is implemented by Frames that can unmarshal their payload
+// without copying it.
+type aliasingFrame interface {
+	unmarshalFrame(hdr *Header, b []byte, alias bool) error
+}
+
+// frameCache holds one reusable instance of the most frequently received
+// Frames, used by Reader when AliasPayloads is enabled.
+type frameCache struct {
+	data         Data
+	headers      Headers
+	continuation Continuation
+	windowUpdate WindowUpdate
+	ping         Ping
+}
+
+// get returns the cached Frame for Type t, reset to its zero value, or a
+// new Frame if t is not cached.
+func (c *frameCache) get(t Type) Frame {
+	switch t {
+	case TypeData:
+		c.data = Data{}
+		return &c.data
+	case TypeHeaders:
+		c.headers = Headers{}
+		return &c.headers
+	case TypeContinuation:
+		c.continuation = Continuation{}
+		return &c.continuation
+	case TypeWindowUpdate:
+		c.windowUpdate = WindowUpdate{}
+		return &c.windowUpdate
+	case TypePing:
+		c.ping = Ping{}
+		return &c.ping
+	default:
+		return newFrame(t)
+	}
+}
+
+// discard consumes the payload of a Frame that exceeds MaxFrameSize. Frames
+// that may alter the state of the entire connection result in a connection
+// error, all others result in a stream error.
+// RFC 7540 Section 4.2
+func (r *Reader) discard(hdr *Header) error {
+	switch hdr.Type {
+	case TypeHeaders, TypePushPromise, TypeContinuation, TypeSettings:
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "reader: frame too large"}
+	}
+
+	if hdr.StreamID == 0 {
+		return ConnectionError{Code: ErrorCodeFrameSize, Reason: "reader: frame too large"}
+	}
+
+	if _, err := io.CopyN(io.Discard, r.r, int64(hdr.Length)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return err
+	}
+
+	return StreamError{StreamID: hdr.StreamID, Code: ErrorCodeFrameSize, Reason: "reader: frame too large"}
+}
+
+// newFrame returns a new, empty Frame for Type t, or an UnknownFrame if t is
+// neither understood by this package nor registered with RegisterFrame.
+func newFrame(t Type) Frame {
+	if f := builtinFrame(t); f != nil {
+		return f
+	}
+
+	return newExtensionFrame(t)
+}
+
+// builtinFrame returns a new, empty Frame for Type t, or nil if t is not
+// understood by this package.
+func builtinFrame(t Type) Frame {
+	switch t {
+	case TypeData:
+		return new(Data)
+	case TypeHeaders:
+		return new(Headers)
+	case TypePriority:
+		return new(Priority)
+	case TypeResetStream:
+		return new(ResetStream)
+	case TypeSettings:
+		return new(Settings)
+	case TypePushPromise:
+		return new(PushPromise)
+	case TypePing:
+		return new(Ping)
+	case TypeGoAway:
+		return new(GoAway)
+	case TypeWindowUpdate:
+		return new(WindowUpdate)
+	case TypeContinuation:
+		return new(Continuation)
+	case TypeAltSvc:
+		return new(AltSvc)
+	case TypeOrigin:
+		return new(Origin)
+	case TypePriorityUpdate:
+		return new(PriorityUpdate)
+	default:
+		return nil
+	}
+}
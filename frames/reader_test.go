@@ -0,0 +1,228 @@
+package frames
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/jamescun/http2/settings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderReadFrame(t *testing.T) {
+	tests := []struct {
+		Name         string
+		MaxFrameSize uint32
+		Bytes        []byte
+		Frames       []Frame
+		Error        error
+	}{
+		{
+			"EOF",
+			DefaultMaxFrameSize,
+			[]byte{},
+			nil,
+			io.EOF,
+		},
+		{
+			"ShortHeader",
+			DefaultMaxFrameSize,
+			[]byte{0x00, 0x00, 0x08, 0x06},
+			nil,
+			io.ErrUnexpectedEOF,
+		},
+		{
+			"ShortPayload",
+			DefaultMaxFrameSize,
+			[]byte{0x00, 0x00, 0x08, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02},
+			nil,
+			io.ErrUnexpectedEOF,
+		},
+		{
+			"Frames",
+			DefaultMaxFrameSize,
+			[]byte{
+				0x00, 0x00, 0x00, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x08, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+				0x00, 0x00, 0x05, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 'h', 'e', 'l', 'l', 'o',
+			},
+			[]Frame{
+				&Settings{
+					Header: Header{Type: TypeSettings, Flags: FlagSettingsAck},
+					Ack:    true,
+				},
+				&Ping{
+					Header: Header{Length: 8, Type: TypePing},
+					Data:   [8]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+				},
+				&Data{
+					Header:    Header{Length: 5, Type: TypeData, Flags: FlagDataEndStream, StreamID: 1},
+					EndStream: true,
+					Data:      []byte("hello"),
+				},
+			},
+			io.EOF,
+		},
+		{
+			"Unknown",
+			DefaultMaxFrameSize,
+			[]byte{0x00, 0x00, 0x02, 0xFF, 0x01, 0x00, 0x00, 0x00, 0x03, 0xAA, 0xBB},
+			[]Frame{
+				&UnknownFrame{
+					Header:  Header{Length: 2, Type: Type(0xFF), Flags: Flags(0x01), StreamID: 3},
+					Payload: []byte{0xAA, 0xBB},
+				},
+			},
+			io.EOF,
+		},
+		{
+			"TooLargeStream",
+			4,
+			[]byte{
+				0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 'h', 'e', 'l', 'l', 'o',
+			},
+			nil,
+			StreamError{StreamID: 1, Code: ErrorCodeFrameSize, Reason: "reader: frame too large"},
+		},
+		{
+			"TooLargeConnection",
+			4,
+			[]byte{
+				0x00, 0x00, 0x05, 0x01, 0x04, 0x00, 0x00, 0x00, 0x01, 0x82, 0x86, 0x84, 0x41, 0x8a,
+			},
+			nil,
+			ConnectionError{Code: ErrorCodeFrameSize, Reason: "reader: frame too large"},
+		},
+		{
+			"Invalid",
+			DefaultMaxFrameSize,
+			[]byte{0x00, 0x00, 0x08, 0x06, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			nil,
+			ConnectionError{Code: ErrorCodeProtocol, Reason: "ping: non-zero stream id"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			r := NewReader(bytes.NewReader(test.Bytes))
+			r.MaxFrameSize = settings.MaxFrameSize{Size: test.MaxFrameSize}
+
+			var frames []Frame
+			var err error
+
+			for {
+				var frame Frame
+				frame, err = r.ReadFrame()
+				if err != nil {
+					break
+				}
+
+				frames = append(frames, frame)
+			}
+
+			assert.Equal(t, test.Frames, frames)
+			assert.Equal(t, test.Error, err)
+		})
+	}
+}
+
+func TestReaderReadFrameStreamError(t *testing.T) {
+	r := NewReader(bytes.NewReader([]byte{
+		0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 'h', 'e', 'l', 'l', 'o',
+		0x00, 0x00, 0x04, 0x03, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x08,
+	}))
+	r.MaxFrameSize = settings.MaxFrameSize{Size: 4}
+
+	_, err := r.ReadFrame()
+	assert.Equal(t, StreamError{StreamID: 1, Code: ErrorCodeFrameSize, Reason: "reader: frame too large"}, err)
+
+	frame, err := r.ReadFrame()
+	if assert.NoError(t, err) {
+		assert.Equal(t, &ResetStream{Header: Header{Length: 4, Type: TypeResetStream, StreamID: 1}, Code: ErrorCodeCancel}, frame)
+	}
+}
+
+func TestReaderReadFrameAliasPayloads(t *testing.T) {
+	b := []byte{
+		0x00, 0x00, 0x05, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 'h', 'e', 'l', 'l', 'o',
+		0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 'w', 'o', 'r', 'l', 'd',
+	}
+
+	r := NewReader(bytes.NewReader(b))
+	r.AliasPayloads = true
+
+	first, err := r.ReadFrame()
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Data{
+			Header:    Header{Length: 5, Type: TypeData, Flags: FlagDataEndStream, StreamID: 1},
+			EndStream: true,
+			Data:      []byte("hello"),
+		}, first)
+	}
+
+	second, err := r.ReadFrame()
+	if assert.NoError(t, err) {
+		assert.Equal(t, &Data{
+			Header: Header{Length: 5, Type: TypeData, StreamID: 3},
+			Data:   []byte("world"),
+		}, second)
+	}
+
+	// NOTE(jc): aliased frames are reused and only valid until the next read.
+	assert.Same(t, first, second)
+}
+
+func TestReaderReadFrameAllocs(t *testing.T) {
+	r := NewReader(&repeatReader{b: []byte{
+		0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 'h', 'e', 'l', 'l', 'o',
+	}})
+	r.AliasPayloads = true
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := r.ReadFrame(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkReaderReadFrameData(b *testing.B) {
+	payload := make([]byte, DefaultMaxFrameSize)
+	frame := append([]byte{0x00, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, payload...)
+
+	for _, alias := range []bool{false, true} {
+		name := "Copy"
+		if alias {
+			name = "Alias"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			r := NewReader(&repeatReader{b: frame})
+			r.AliasPayloads = alias
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(frame)))
+
+			for i := 0; i < b.N; i++ {
+				if _, err := r.ReadFrame(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// repeatReader endlessly repeats b.
+type repeatReader struct {
+	b   []byte
+	off int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	n := copy(p, r.b[r.off:])
+	r.off = (r.off + n) % len(r.b)
+
+	return n, nil
+}
@@ -0,0 +1,114 @@
+package frames
+
+import (
+	"io"
+	"sync"
+)
+
+// maxPooledBuffer is the largest buffer returned to bufferPool, larger
+// buffers are left to the garbage collector.
+const maxPooledBuffer = 64 << 10
+
+// bufferPool holds write buffers shared between all Writers, so that idle
+// connections do not each retain a buffer.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 4<<10)
+		return &b
+	},
+}
+
+// Writer writes Frames to an underlying io.Writer, such as a net.Conn. Each
+// Frame header and payload are written contiguously in a single call to the
+// underlying io.Writer.
+//
+// Small control frames that do not need to be sent immediately, Settings
+// acknowledgements, Ping acknowledgements and WindowUpdate frames, are
+// buffered and written together with the next Frame or on Flush.
+//
+// Writer borrows its buffer from a pool shared by all Writers for only as
+// long as Frames are buffered. Frames implementing Appender are marshalled
+// directly into this buffer without any allocations.
+type Writer struct {
+	w   io.Writer
+	buf *[]byte
+}
+
+// NewWriter returns a Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// WriteFrame marshals f and writes it, along with any previously buffered
+// Frames, to the underlying io.Writer. If f can be batched it is buffered
+// until the next call to WriteFrame or Flush.
+func (w *Writer) WriteFrame(f Frame) error {
+	if w.buf == nil {
+		w.buf = bufferPool.Get().(*[]byte)
+	}
+
+	b, err := AppendFrame(*w.buf, f)
+	if err != nil {
+		return err
+	}
+
+	*w.buf = b
+
+	if batchable(f) {
+		return nil
+	}
+
+	return w.Flush()
+}
+
+// Flush writes any buffered Frames to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	if w.buf == nil {
+		return nil
+	}
+
+	var err error
+
+	if b := *w.buf; len(b) > 0 {
+		var n int
+
+		n, err = w.w.Write(b)
+		if err == nil && n < len(b) {
+			err = io.ErrShortWrite
+		}
+	}
+
+	if cap(*w.buf) <= maxPooledBuffer {
+		*w.buf = (*w.buf)[:0]
+		bufferPool.Put(w.buf)
+	}
+
+	w.buf = nil
+
+	return err
+}
+
+// Buffered returns the number of bytes buffered but not yet written to the
+// underlying io.Writer.
+func (w *Writer) Buffered() int {
+	if w.buf == nil {
+		return 0
+	}
+
+	return len(*w.buf)
+}
+
+// batchable returns true if Frame f is a small control frame that need not
+// be written immediately.
+func batchable(f Frame) bool {
+	switch f := f.(type) {
+	case *Settings:
+		return f.Ack
+	case *Ping:
+		return f.Ack
+	case *WindowUpdate:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,134 @@
+package frames
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (c *countWriter) Write(b []byte) (int, error) {
+	c.writes++
+	return c.Buffer.Write(b)
+}
+
+func TestWriterWriteFrame(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Frames   []Frame
+		Flush    bool
+		Bytes    []byte
+		Writes   int
+		Buffered int
+	}{
+		{
+			"Data",
+			[]Frame{&Data{Header: Header{StreamID: 1}, EndStream: true, Data: []byte("hello")}},
+			false,
+			[]byte{0x00, 0x00, 0x05, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 'h', 'e', 'l', 'l', 'o'},
+			1,
+			0,
+		},
+		{
+			"Batched",
+			[]Frame{
+				&Settings{Ack: true},
+				&WindowUpdate{Increment: 65535},
+			},
+			false,
+			nil,
+			0,
+			22,
+		},
+		{
+			"BatchedFlush",
+			[]Frame{
+				&Settings{Ack: true},
+				&Ping{Ack: true},
+			},
+			true,
+			[]byte{
+				0x00, 0x00, 0x00, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x08, 0x06, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			1,
+			0,
+		},
+		{
+			"BatchedData",
+			[]Frame{
+				&WindowUpdate{Increment: 65535},
+				&Data{Header: Header{StreamID: 1}, Data: []byte("hi")},
+			},
+			false,
+			[]byte{
+				0x00, 0x00, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF,
+				0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 'h', 'i',
+			},
+			1,
+			0,
+		},
+		{
+			"Ping",
+			[]Frame{&Ping{}},
+			false,
+			[]byte{0x00, 0x00, 0x08, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			1,
+			0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cw := new(countWriter)
+			w := NewWriter(cw)
+
+			for _, frame := range test.Frames {
+				if !assert.NoError(t, w.WriteFrame(frame)) {
+					return
+				}
+			}
+
+			if test.Flush {
+				assert.NoError(t, w.Flush())
+			}
+
+			assert.Equal(t, test.Bytes, cw.Bytes())
+			assert.Equal(t, test.Writes, cw.writes)
+			assert.Equal(t, test.Buffered, w.Buffered())
+		})
+	}
+}
+
+func TestWriterWriteFrameAllocs(t *testing.T) {
+	w := NewWriter(io.Discard)
+	frame := &Data{Header: Header{StreamID: 1}, Data: []byte("hello")}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := w.WriteFrame(frame); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkWriterWriteFrameData(b *testing.B) {
+	w := NewWriter(io.Discard)
+	frame := &Data{Header: Header{StreamID: 1}, Data: make([]byte, DefaultMaxFrameSize)}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(HeaderLength + len(frame.Data)))
+
+	for i := 0; i < b.N; i++ {
+		if err := w.WriteFrame(frame); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package hpack
+
+// maxInteger is the largest integer accepted by parseInteger, no integer
+// encoded by HPACK (index, string length or table size) can exceed it.
+const maxInteger = 1<<32 - 1
+
+// appendInteger appends v encoded with an n-bit prefix to b, the remaining
+// high bits of the first byte are set to flags.
+// RFC 7541 Section 5.1
+func appendInteger(b []byte, flags byte, n uint8, v uint64) []byte {
+	limit := uint64(1)<<n - 1
+
+	if v < limit {
+		return append(b, flags|byte(v))
+	}
+
+	b = append(b, flags|byte(limit))
+	v -= limit
+
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+
+	return append(b, byte(v))
+}
+
+// parseInteger decodes an integer with an n-bit prefix from the beginning of
+// b, returning it and the remainder of b.
+// RFC 7541 Section 5.1
+func parseInteger(b []byte, n uint8) (uint64, []byte, error) {
+	if len(b) < 1 {
+		return 0, nil, compressionError("hpack: short integer")
+	}
+
+	limit := uint64(1)<<n - 1
+
+	v := uint64(b[0]) & limit
+	b = b[1:]
+
+	if v < limit {
+		return v, b, nil
+	}
+
+	for shift := uint(0); ; shift += 7 {
+		if len(b) < 1 {
+			return 0, nil, compressionError("hpack: short integer")
+		} else if shift > 28 {
+			return 0, nil, compressionError("hpack: integer overflow")
+		}
+
+		c := b[0]
+		b = b[1:]
+
+		v += uint64(c&0x7f) << shift
+		if v > maxInteger {
+			return 0, nil, compressionError("hpack: integer overflow")
+		}
+
+		if c&0x80 == 0 {
+			return v, b, nil
+		}
+	}
+}
+
+// appendString appends s encoded as a string literal to b, Huffman encoded
+// only if it is shorter than s.
+// RFC 7541 Section 5.2
+func appendString(b []byte, s string) []byte {
+	if n := huffmanLen(s); n < len(s) {
+		b = appendInteger(b, 0x80, 7, uint64(n))
+
+		return appendHuffman(b, s)
+	}
+
+	b = appendInteger(b, 0, 7, uint64(len(s)))
+
+	return append(b, s...)
+}
+
+// parseString decodes a string literal from the beginning of b, returning it
+// and the remainder of b.
+// RFC 7541 Section 5.2
+func parseString(b []byte) (string, []byte, error) {
+	if len(b) < 1 {
+		return "", nil, compressionError("hpack: short string")
+	}
+
+	huffman := b[0]&0x80 != 0
+
+	n, b, err := parseInteger(b, 7)
+	if err != nil {
+		return "", nil, err
+	} else if uint64(len(b)) < n {
+		return "", nil, compressionError("hpack: short string")
+	}
+
+	if huffman {
+		s, err := appendHuffmanDecode(make([]byte, 0, n*8/5), b[:n])
+		if err != nil {
+			return "", nil, err
+		}
+
+		return string(s), b[n:], nil
+	}
+
+	return string(b[:n]), b[n:], nil
+}
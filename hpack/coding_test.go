@@ -0,0 +1,98 @@
+package hpack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendInteger(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Flags  byte
+		Prefix uint8
+		Value  uint64
+		Bytes  []byte
+	}{
+		{"10Prefix5", 0, 5, 10, []byte{0x0a}},
+		{"1337Prefix5", 0, 5, 1337, []byte{0x1f, 0x9a, 0x0a}},
+		{"42Prefix8", 0, 8, 42, []byte{0x2a}},
+		{"Limit", 0, 5, 31, []byte{0x1f, 0x00}},
+		{"Flags", 0x80, 7, 2, []byte{0x82}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			b := appendInteger(nil, test.Flags, test.Prefix, test.Value)
+
+			assert.Equal(t, test.Bytes, b)
+		})
+	}
+}
+
+func TestParseInteger(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Bytes  []byte
+		Prefix uint8
+		Value  uint64
+		Rest   []byte
+		Error  error
+	}{
+		{"10Prefix5", []byte{0xea}, 5, 10, []byte{}, nil},
+		{"1337Prefix5", []byte{0x1f, 0x9a, 0x0a, 0xff}, 5, 1337, []byte{0xff}, nil},
+		{"42Prefix8", []byte{0x2a}, 8, 42, []byte{}, nil},
+		{"Max", []byte{0x1f, 0xe0, 0xff, 0xff, 0xff, 0x0f}, 5, maxInteger, []byte{}, nil},
+		{"Empty", []byte{}, 5, 0, nil, compressionError("hpack: short integer")},
+		{"Short", []byte{0x1f, 0x9a}, 5, 0, nil, compressionError("hpack: short integer")},
+		{"Overflow", []byte{0x1f, 0xe1, 0xff, 0xff, 0xff, 0x0f}, 5, 0, nil, compressionError("hpack: integer overflow")},
+		{"TooLong", []byte{0x1f, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, 5, 0, nil, compressionError("hpack: integer overflow")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			v, rest, err := parseInteger(test.Bytes, test.Prefix)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Value, v)
+					assert.Equal(t, test.Rest, rest)
+				}
+			} else {
+				assert.Equal(t, test.Error, err)
+			}
+		})
+	}
+}
+
+func TestParseString(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Bytes []byte
+		Value string
+		Rest  []byte
+		Error error
+	}{
+		{"Empty", []byte{0x00}, "", []byte{}, nil},
+		{"Literal", []byte{0x03, 'f', 'o', 'o', 0x82}, "foo", []byte{0x82}, nil},
+		{"Huffman", unhex("86 a8eb 1064 9cbf 82"), "no-cache", []byte{0x82}, nil},
+		{"HuffmanInvalid", unhex("84 ffff ffff"), "", nil, compressionError("hpack: huffman encoded EOS")},
+		{"Short", []byte{0x03, 'f', 'o'}, "", nil, compressionError("hpack: short string")},
+		{"Missing", []byte{}, "", nil, compressionError("hpack: short string")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			v, rest, err := parseString(test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Value, v)
+					assert.Equal(t, test.Rest, rest)
+				}
+			} else {
+				assert.Equal(t, test.Error, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package hpack
+
+import (
+	"github.com/jamescun/http2/settings"
+)
+
+// Decoder decompresses header blocks into lists of header fields. A Decoder
+// maintains a dynamic table shared with the Encoder of its peer, so MUST only
+// be used by a single connection, decoding complete header blocks in the
+// order they are received. Any error returned by a Decoder is a connection
+// error of type COMPRESSION_ERROR, after which it MUST NOT be used.
+type Decoder struct {
+	// MaxHeaderListSize is the largest uncompressed header list a header
+	// block may decode to, as advertised with settings.MaxHeaderListSize,
+	// where the size of each field is calculated as in the dynamic table.
+	MaxHeaderListSize settings.MaxHeaderListSize
+
+	table dynamicTable
+
+	// maxSize is the largest maximum size of the dynamic table the peer may
+	// signal, as advertised with settings.HeaderTableSize.
+	maxSize uint32
+
+	// pending is true when maxSize has been reduced, the next header block
+	// MUST begin with a dynamic table size update.
+	pending bool
+}
+
+// NewDecoder returns a new Decoder with a dynamic table of
+// DefaultHeaderTableSize and a MaxHeaderListSize of DefaultMaxHeaderListSize.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		MaxHeaderListSize: settings.MaxHeaderListSize{Size: DefaultMaxHeaderListSize},
+		table:             dynamicTable{maxSize: DefaultHeaderTableSize},
+		maxSize:           DefaultHeaderTableSize,
+	}
+}
+
+// SetHeaderTableSize changes the largest maximum size of the dynamic table
+// the peer may use, after a settings.HeaderTableSize sent to it has been
+// acknowledged. If the size is reduced the dynamic table is shrunk
+// immediately, and the next header block MUST begin with a dynamic table size
+// update no larger than it.
+// RFC 7541 Section 4.2
+func (d *Decoder) SetHeaderTableSize(s settings.HeaderTableSize) {
+	if s.Size < d.table.maxSize {
+		d.table.setMaxSize(s.Size)
+		d.pending = true
+	}
+
+	d.maxSize = s.Size
+}
+
+// DecodeHeaderBlock decodes a complete header block into a list of header
+// fields, updating the dynamic table. Decoding stops with an error once the
+// header list exceeds MaxHeaderListSize, protecting against header blocks
+// that repeatedly reference large fields in the dynamic table.
+// RFC 7541 Section 6
+func (d *Decoder) DecodeHeaderBlock(block []byte) ([]HeaderField, error) {
+	if d.pending && (len(block) == 0 || block[0]&0xe0 != 0x20) {
+		return nil, compressionError("hpack: missing table size update")
+	}
+
+	var (
+		fields []HeaderField
+		size   uint64
+	)
+
+	for len(block) > 0 {
+		var (
+			f   HeaderField
+			err error
+		)
+
+		switch c := block[0]; {
+		case c&0x80 != 0:
+			f, block, err = d.parseIndexed(block)
+
+		case c&0xc0 == 0x40:
+			f, block, err = d.parseLiteral(block, 6)
+			if err == nil {
+				d.table.add(f)
+			}
+
+		case c&0xe0 == 0x20:
+			// NOTE(jc): dynamic table size updates MUST occur at the
+			// beginning of a header block.
+			if len(fields) > 0 {
+				return nil, compressionError("hpack: table size update after header field")
+			}
+
+			block, err = d.parseSizeUpdate(block)
+			if err != nil {
+				return nil, err
+			}
+
+			d.pending = false
+
+			continue
+
+		case c&0xf0 == 0x10:
+			f, block, err = d.parseLiteral(block, 4)
+			f.Sensitive = true
+
+		default:
+			f, block, err = d.parseLiteral(block, 4)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// NOTE(jc): the dynamic table can no longer be kept in sync with the
+		// peer, so this MUST be treated as a connection error.
+		size += uint64(f.Size())
+		if size > uint64(d.MaxHeaderListSize.Size) {
+			return nil, compressionError("hpack: header list too large")
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// parseIndexed decodes an indexed header field representation.
+// RFC 7541 Section 6.1
+func (d *Decoder) parseIndexed(b []byte) (HeaderField, []byte, error) {
+	i, b, err := parseInteger(b, 7)
+	if err != nil {
+		return HeaderField{}, nil, err
+	}
+
+	f, ok := d.table.field(i)
+	if !ok {
+		return HeaderField{}, nil, compressionError("hpack: invalid index")
+	}
+
+	return f, b, nil
+}
+
+// parseLiteral decodes a literal header field representation with an n-bit
+// name index prefix.
+// RFC 7541 Section 6.2
+func (d *Decoder) parseLiteral(b []byte, n uint8) (HeaderField, []byte, error) {
+	i, b, err := parseInteger(b, n)
+	if err != nil {
+		return HeaderField{}, nil, err
+	}
+
+	var f HeaderField
+
+	if i == 0 {
+		f.Name, b, err = parseString(b)
+		if err != nil {
+			return HeaderField{}, nil, err
+		}
+	} else {
+		name, ok := d.table.field(i)
+		if !ok {
+			return HeaderField{}, nil, compressionError("hpack: invalid index")
+		}
+
+		f.Name = name.Name
+	}
+
+	f.Value, b, err = parseString(b)
+	if err != nil {
+		return HeaderField{}, nil, err
+	}
+
+	return f, b, nil
+}
+
+// parseSizeUpdate decodes a dynamic table size update, which MUST NOT exceed
+// the size advertised with settings.HeaderTableSize.
+// RFC 7541 Section 6.3
+func (d *Decoder) parseSizeUpdate(b []byte) ([]byte, error) {
+	size, b, err := parseInteger(b, 5)
+	if err != nil {
+		return nil, err
+	} else if size > uint64(d.maxSize) {
+		return nil, compressionError("hpack: table size update too large")
+	}
+
+	d.table.setMaxSize(uint32(size))
+
+	return b, nil
+}
@@ -0,0 +1,121 @@
+package hpack
+
+import (
+	"github.com/jamescun/http2/settings"
+)
+
+// Encoder compresses lists of header fields into header blocks. An Encoder
+// maintains a dynamic table shared with the Decoder of its peer, so MUST only
+// be used by a single connection, encoding header blocks in the order they
+// are sent.
+type Encoder struct {
+	// Sensitive, if not nil, is consulted for each field not already marked
+	// as Sensitive, those it reports as sensitive are encoded as literals
+	// never indexed.
+	Sensitive SensitivePolicy
+
+	// MaxHeaderTableSize is the largest dynamic table the Encoder will use,
+	// regardless of the settings.HeaderTableSize advertised by the peer,
+	// limiting the memory held by a long-lived connection.
+	MaxHeaderTableSize settings.HeaderTableSize
+
+	table dynamicTable
+
+	// pending is true when the maximum size of the dynamic table has changed
+	// since the last header block, minSize is the smallest maximum size set
+	// in that time.
+	pending bool
+	minSize uint32
+}
+
+// NewEncoder returns a new Encoder with a dynamic table and a
+// MaxHeaderTableSize of DefaultHeaderTableSize, using DefaultSensitivePolicy.
+func NewEncoder() *Encoder {
+	return &Encoder{
+		Sensitive:          DefaultSensitivePolicy,
+		MaxHeaderTableSize: settings.HeaderTableSize{Size: DefaultHeaderTableSize},
+		table:              dynamicTable{maxSize: DefaultHeaderTableSize},
+	}
+}
+
+// SetHeaderTableSize changes the maximum size of the dynamic table, after a
+// settings.HeaderTableSize has been received from the peer, to no more than
+// MaxHeaderTableSize. The change is signalled at the beginning of the next
+// header block.
+// RFC 7541 Section 4.2
+func (e *Encoder) SetHeaderTableSize(s settings.HeaderTableSize) {
+	size := s.Size
+	if size > e.MaxHeaderTableSize.Size {
+		size = e.MaxHeaderTableSize.Size
+	}
+
+	if !e.pending || size < e.minSize {
+		e.minSize = size
+	}
+
+	e.pending = true
+	e.table.setMaxSize(size)
+}
+
+// AppendHeaderBlock appends fields encoded as a header block to b and
+// returns the extended buffer. Sensitive fields are encoded as literals never
+// indexed, fields found in the static or dynamic table are encoded as an
+// index, otherwise they are encoded as a literal and inserted into the
+// dynamic table.
+// RFC 7541 Section 6
+func (e *Encoder) AppendHeaderBlock(b []byte, fields []HeaderField) []byte {
+	if e.pending {
+		// NOTE(jc): if the maximum size was reduced and then increased since
+		// the last header block, the smallest size MUST be signalled first.
+		if e.minSize < e.table.maxSize {
+			b = appendInteger(b, 0x20, 5, uint64(e.minSize))
+		}
+
+		b = appendInteger(b, 0x20, 5, uint64(e.table.maxSize))
+		e.pending = false
+	}
+
+	for _, f := range fields {
+		b = e.appendField(b, f)
+	}
+
+	return b
+}
+
+// appendField appends the representation of a single field to b.
+func (e *Encoder) appendField(b []byte, f HeaderField) []byte {
+	i, exact := e.table.search(f)
+
+	// NOTE(jc): sensitive fields never enter the dynamic table, nor are they
+	// encoded as an index of it, only their name may be indexed.
+	if f.Sensitive || (e.Sensitive != nil && e.Sensitive(f)) {
+		return appendLiteral(b, 0x10, 4, i, f)
+	}
+
+	if exact {
+		return appendInteger(b, 0x80, 7, i)
+	}
+
+	// NOTE(jc): a field larger than the dynamic table would only empty it,
+	// so is sent as a literal without indexing instead.
+	if f.Size() > e.table.maxSize {
+		return appendLiteral(b, 0x00, 4, i, f)
+	}
+
+	e.table.add(f)
+
+	return appendLiteral(b, 0x40, 6, i, f)
+}
+
+// appendLiteral appends f as a literal representation to b, with its name
+// given as index i if non-zero.
+// RFC 7541 Section 6.2
+func appendLiteral(b []byte, flags byte, n uint8, i uint64, f HeaderField) []byte {
+	b = appendInteger(b, flags, n, i)
+
+	if i == 0 {
+		b = appendString(b, f.Name)
+	}
+
+	return appendString(b, f.Value)
+}
@@ -0,0 +1,52 @@
+// Package hpack implements HPACK, the header compression format used to
+// encode the header blocks carried by HTTP/2 Headers, PushPromise and
+// Continuation frames.
+// RFC 7541
+package hpack
+
+import (
+	"github.com/jamescun/http2/frames"
+)
+
+// DefaultHeaderTableSize is the initial maximum size of the dynamic table of
+// both an Encoder and Decoder, in bytes, until changed by
+// settings.HeaderTableSize.
+// RFC 7540 Section 6.5.2
+const DefaultHeaderTableSize = 4096
+
+// DefaultMaxHeaderListSize is the initial MaxHeaderListSize of a Decoder, in
+// bytes. It SHOULD be advertised to the peer with settings.MaxHeaderListSize.
+const DefaultMaxHeaderListSize = 1 << 20
+
+// entryOverhead is the number of bytes added to the length of the name and
+// value of a HeaderField when calculating its size in the dynamic table.
+// RFC 7541 Section 4.1
+const entryOverhead = 32
+
+// HeaderField is a single header name and value. Pseudo-header fields begin
+// with a colon.
+// RFC 7541 Section 1.3
+type HeaderField struct {
+	Name  string
+	Value string
+
+	// Sensitive indicates the field MUST always be encoded as a literal
+	// never indexed, and is set on fields decoded from such literals so
+	// intermediaries re-encode them the same way.
+	// RFC 7541 Section 7.1.3
+	Sensitive bool
+}
+
+// Size returns the size of a HeaderField when stored in the dynamic table, in
+// bytes.
+// RFC 7541 Section 4.1
+func (h HeaderField) Size() uint32 {
+	return uint32(len(h.Name) + len(h.Value) + entryOverhead)
+}
+
+// compressionError returns a connection error of type COMPRESSION_ERROR,
+// which MUST be returned for any header block that cannot be decoded.
+// RFC 7540 Section 4.3
+func compressionError(reason string) error {
+	return frames.ConnectionError{Code: frames.ErrorCodeCompression, Reason: reason}
+}
@@ -0,0 +1,515 @@
+package hpack
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/jamescun/http2/settings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unhex decodes s as hexadecimal, ignoring any whitespace.
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// headerBlock is a single header block of a sequence encoded by the same
+// Encoder, or decoded by the same Decoder.
+type headerBlock struct {
+	Bytes     []byte
+	Fields    []HeaderField
+	TableSize uint32
+}
+
+var (
+	// requestsWithoutHuffman are the examples from RFC 7541 Appendix C.3.
+	requestsWithoutHuffman = []headerBlock{
+		{
+			unhex("8286 8441 0f77 7777 2e65 7861 6d70 6c65 2e63 6f6d"),
+			[]HeaderField{
+				{Name: ":method", Value: "GET"},
+				{Name: ":scheme", Value: "http"},
+				{Name: ":path", Value: "/"},
+				{Name: ":authority", Value: "www.example.com"},
+			},
+			57,
+		},
+		{
+			unhex("8286 84be 5808 6e6f 2d63 6163 6865"),
+			[]HeaderField{
+				{Name: ":method", Value: "GET"},
+				{Name: ":scheme", Value: "http"},
+				{Name: ":path", Value: "/"},
+				{Name: ":authority", Value: "www.example.com"},
+				{Name: "cache-control", Value: "no-cache"},
+			},
+			110,
+		},
+		{
+			unhex("8287 85bf 400a 6375 7374 6f6d 2d6b 6579 0c63 7573 746f 6d2d 7661 6c75 65"),
+			[]HeaderField{
+				{Name: ":method", Value: "GET"},
+				{Name: ":scheme", Value: "https"},
+				{Name: ":path", Value: "/index.html"},
+				{Name: ":authority", Value: "www.example.com"},
+				{Name: "custom-key", Value: "custom-value"},
+			},
+			164,
+		},
+	}
+
+	// requestsWithHuffman are the examples from RFC 7541 Appendix C.4.
+	requestsWithHuffman = []headerBlock{
+		{
+			unhex("8286 8441 8cf1 e3c2 e5f2 3a6b a0ab 90f4 ff"),
+			requestsWithoutHuffman[0].Fields,
+			57,
+		},
+		{
+			unhex("8286 84be 5886 a8eb 1064 9cbf"),
+			requestsWithoutHuffman[1].Fields,
+			110,
+		},
+		{
+			unhex("8287 85bf 4088 25a8 49e9 5ba9 7d7f 8925 a849 e95b b8e8 b4bf"),
+			requestsWithoutHuffman[2].Fields,
+			164,
+		},
+	}
+
+	// responsesWithoutHuffman are the examples from RFC 7541 Appendix C.5,
+	// with a maximum dynamic table size of 256 bytes.
+	responsesWithoutHuffman = []headerBlock{
+		{
+			unhex(`4803 3330 3258 0770 7269 7661 7465 611d
+			4d6f 6e2c 2032 3120 4f63 7420 3230 3133
+			2032 303a 3133 3a32 3120 474d 546e 1768
+			7474 7073 3a2f 2f77 7777 2e65 7861 6d70
+			6c65 2e63 6f6d`),
+			[]HeaderField{
+				{Name: ":status", Value: "302"},
+				{Name: "cache-control", Value: "private"},
+				{Name: "date", Value: "Mon, 21 Oct 2013 20:13:21 GMT"},
+				{Name: "location", Value: "https://www.example.com"},
+			},
+			222,
+		},
+		{
+			unhex("4803 3330 37c1 c0bf"),
+			[]HeaderField{
+				{Name: ":status", Value: "307"},
+				{Name: "cache-control", Value: "private"},
+				{Name: "date", Value: "Mon, 21 Oct 2013 20:13:21 GMT"},
+				{Name: "location", Value: "https://www.example.com"},
+			},
+			222,
+		},
+		{
+			unhex(`88c1 611d 4d6f 6e2c 2032 3120 4f63 7420
+			3230 3133 2032 303a 3133 3a32 3220 474d
+			54c0 5a04 677a 6970 7738 666f 6f3d 4153
+			444a 4b48 514b 425a 584f 5157 454f 5049
+			5541 5851 5745 4f49 553b 206d 6178 2d61
+			6765 3d33 3630 303b 2076 6572 7369 6f6e
+			3d31`),
+			[]HeaderField{
+				{Name: ":status", Value: "200"},
+				{Name: "cache-control", Value: "private"},
+				{Name: "date", Value: "Mon, 21 Oct 2013 20:13:22 GMT"},
+				{Name: "location", Value: "https://www.example.com"},
+				{Name: "content-encoding", Value: "gzip"},
+				{Name: "set-cookie", Value: "foo=ASDJKHQKBZXOQWEOPIUAXQWEOIU; max-age=3600; version=1"},
+			},
+			215,
+		},
+	}
+	// responsesWithHuffman are the examples from RFC 7541 Appendix C.6,
+	// with a maximum dynamic table size of 256 bytes.
+	responsesWithHuffman = []headerBlock{
+		{
+			unhex(`4882 6402 5885 aec3 771a 4b61 96d0 7abe
+			9410 54d4 44a8 2005 9504 0b81 66e0 82a6
+			2d1b ff6e 919d 29ad 1718 63c7 8f0b 97c8
+			e9ae 82ae 43d3`),
+			responsesWithoutHuffman[0].Fields,
+			222,
+		},
+		{
+			unhex("4883 640e ffc1 c0bf"),
+			responsesWithoutHuffman[1].Fields,
+			222,
+		},
+		{
+			unhex(`88c1 6196 d07a be94 1054 d444 a820 0595
+			040b 8166 e084 a62d 1bff c05a 839b d9ab
+			77ad 94e7 821d d7f2 e6c7 b335 dfdf cd5b
+			3960 d5af 2708 7f36 72c1 ab27 0fb5 291f
+			9587 3160 65c0 03ed 4ee5 b106 3d50 07`),
+			responsesWithoutHuffman[2].Fields,
+			215,
+		},
+	}
+)
+
+func TestEncoder(t *testing.T) {
+	tests := []struct {
+		Name      string
+		TableSize uint32
+		Blocks    []headerBlock
+	}{
+		{"RequestsWithHuffman", DefaultHeaderTableSize, requestsWithHuffman},
+		{
+			"ResponsesWithHuffman",
+			256,
+			[]headerBlock{
+				responsesWithHuffman[0],
+				// NOTE(jc): "307" is no shorter when Huffman encoded, so is
+				// sent as a raw string literal unlike RFC 7541 Appendix C.6.2.
+				{unhex("4803 3330 37c1 c0bf"), responsesWithHuffman[1].Fields, 222},
+				responsesWithHuffman[2],
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			e := NewEncoder()
+			e.table.setMaxSize(test.TableSize)
+
+			for _, block := range test.Blocks {
+				b := e.AppendHeaderBlock(nil, block.Fields)
+
+				assert.Equal(t, block.Bytes, b)
+				assert.Equal(t, block.TableSize, e.table.size)
+			}
+		})
+	}
+}
+
+func TestEncoderSetHeaderTableSize(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Sizes  []uint32
+		Prefix []byte
+	}{
+		{"None", nil, nil},
+		{"Decrease", []uint32{256}, unhex("3fe1 01")},
+		{"Capped", []uint32{8192}, unhex("3fe1 1f")},
+		{"DecreaseIncrease", []uint32{0, 4096}, unhex("20 3fe1 1f")},
+		{"Smallest", []uint32{1024, 0, 2048}, unhex("20 3fe1 0f")},
+	}
+
+	fields := []HeaderField{{Name: ":method", Value: "GET"}}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			e := NewEncoder()
+
+			for _, size := range test.Sizes {
+				e.SetHeaderTableSize(settings.HeaderTableSize{Size: size})
+			}
+
+			b := e.AppendHeaderBlock(nil, fields)
+			assert.Equal(t, append(test.Prefix, 0x82), b)
+
+			b = e.AppendHeaderBlock(nil, fields)
+			assert.Equal(t, []byte{0x82}, b)
+		})
+	}
+}
+
+func TestEncoderMaxHeaderTableSize(t *testing.T) {
+	e := NewEncoder()
+	e.MaxHeaderTableSize = settings.HeaderTableSize{Size: 8192}
+	e.SetHeaderTableSize(settings.HeaderTableSize{Size: 1 << 20})
+
+	b := e.AppendHeaderBlock(nil, []HeaderField{{Name: ":method", Value: "GET"}})
+	assert.Equal(t, unhex("3fe1 3f 82"), b)
+	assert.Equal(t, uint32(8192), e.table.maxSize)
+}
+
+func TestEncoderSensitive(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Policy    SensitivePolicy
+		Fields    []HeaderField
+		Result    []HeaderField
+		TableSize uint32
+	}{
+		{
+			"Field",
+			nil,
+			[]HeaderField{{Name: "password", Value: "secret", Sensitive: true}},
+			[]HeaderField{{Name: "password", Value: "secret", Sensitive: true}},
+			0,
+		},
+		{
+			"DefaultPolicy",
+			DefaultSensitivePolicy,
+			[]HeaderField{
+				{Name: "authorization", Value: "secret"},
+				{Name: "cookie", Value: "secret"},
+				{Name: "accept", Value: "*/*"},
+			},
+			[]HeaderField{
+				{Name: "authorization", Value: "secret", Sensitive: true},
+				{Name: "cookie", Value: "secret", Sensitive: true},
+				{Name: "accept", Value: "*/*"},
+			},
+			41,
+		},
+		{
+			"Names",
+			SensitiveNames("x-api-key"),
+			[]HeaderField{
+				{Name: "x-api-key", Value: "secret"},
+				{Name: "authorization", Value: "secret"},
+			},
+			[]HeaderField{
+				{Name: "x-api-key", Value: "secret", Sensitive: true},
+				{Name: "authorization", Value: "secret"},
+			},
+			51,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			e, d := NewEncoder(), NewDecoder()
+			e.Sensitive = test.Policy
+
+			// NOTE(jc): sensitive fields MUST remain literals when encoded
+			// again, as they never enter the dynamic table.
+			for i := 0; i < 2; i++ {
+				fields, err := d.DecodeHeaderBlock(e.AppendHeaderBlock(nil, test.Fields))
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Result, fields)
+					assert.Equal(t, test.TableSize, e.table.size)
+				}
+			}
+		})
+	}
+}
+
+func TestEncoderSensitiveNameIndex(t *testing.T) {
+	b := NewEncoder().AppendHeaderBlock(nil, []HeaderField{{Name: ":method", Value: "GET", Sensitive: true}})
+
+	assert.Equal(t, unhex("1203 4745 54"), b)
+}
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		Name      string
+		TableSize uint32
+		Blocks    []headerBlock
+	}{
+		{
+			"LiteralWithIndexing",
+			DefaultHeaderTableSize,
+			[]headerBlock{{
+				unhex("400a 6375 7374 6f6d 2d6b 6579 0d63 7573 746f 6d2d 6865 6164 6572"),
+				[]HeaderField{{Name: "custom-key", Value: "custom-header"}},
+				55,
+			}},
+		},
+		{
+			"LiteralWithoutIndexing",
+			DefaultHeaderTableSize,
+			[]headerBlock{{
+				unhex("040c 2f73 616d 706c 652f 7061 7468"),
+				[]HeaderField{{Name: ":path", Value: "/sample/path"}},
+				0,
+			}},
+		},
+		{
+			"LiteralNeverIndexed",
+			DefaultHeaderTableSize,
+			[]headerBlock{{
+				unhex("1008 7061 7373 776f 7264 0673 6563 7265 74"),
+				[]HeaderField{{Name: "password", Value: "secret", Sensitive: true}},
+				0,
+			}},
+		},
+		{
+			"Indexed",
+			DefaultHeaderTableSize,
+			[]headerBlock{{unhex("82"), []HeaderField{{Name: ":method", Value: "GET"}}, 0}},
+		},
+		{"RequestsWithoutHuffman", DefaultHeaderTableSize, requestsWithoutHuffman},
+		{"RequestsWithHuffman", DefaultHeaderTableSize, requestsWithHuffman},
+		{"ResponsesWithoutHuffman", 256, responsesWithoutHuffman},
+		{"ResponsesWithHuffman", 256, responsesWithHuffman},
+		{
+			"SizeUpdate",
+			DefaultHeaderTableSize,
+			[]headerBlock{
+				{unhex("4003 666f 6f03 6261 72"), []HeaderField{{Name: "foo", Value: "bar"}}, 38},
+				{unhex("3f07 be"), []HeaderField{{Name: "foo", Value: "bar"}}, 38},
+				{unhex("20 3fe1 1f"), nil, 0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			d := NewDecoder()
+			d.table.setMaxSize(test.TableSize)
+
+			for _, block := range test.Blocks {
+				fields, err := d.DecodeHeaderBlock(block.Bytes)
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, block.Fields, fields)
+					assert.Equal(t, block.TableSize, d.table.size)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoderError(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Bytes []byte
+		Error string
+	}{
+		{"IndexZero", unhex("80"), "hpack: invalid index"},
+		{"IndexOutOfRange", unhex("be"), "hpack: invalid index"},
+		{"NameIndexOutOfRange", unhex("7e03 666f 6f"), "hpack: invalid index"},
+		{"ShortInteger", unhex("ff"), "hpack: short integer"},
+		{"ShortString", unhex("4003 666f"), "hpack: short string"},
+		{"SizeUpdateTooLarge", unhex("3fe2 1f"), "hpack: table size update too large"},
+		{"SizeUpdateAfterField", unhex("82 20"), "hpack: table size update after header field"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fields, err := NewDecoder().DecodeHeaderBlock(test.Bytes)
+
+			assert.Nil(t, fields)
+			assert.Equal(t, compressionError(test.Error), err)
+		})
+	}
+}
+
+func TestDecoderMaxHeaderListSize(t *testing.T) {
+	e := NewEncoder()
+	field := HeaderField{Name: "x-large", Value: strings.Repeat("a", 4000)}
+
+	// NOTE(jc): a single large field in the dynamic table referenced by
+	// every remaining byte of a header block.
+	bomb := e.AppendHeaderBlock(nil, []HeaderField{field})
+	bomb = append(bomb, bytes.Repeat([]byte{0xbe}, 1<<14)...)
+
+	fields, err := NewDecoder().DecodeHeaderBlock(bomb)
+	assert.Nil(t, fields)
+	assert.Equal(t, compressionError("hpack: header list too large"), err)
+
+	d := NewDecoder()
+	d.MaxHeaderListSize = settings.MaxHeaderListSize{Size: 2 * field.Size()}
+
+	fields, err = d.DecodeHeaderBlock(bomb[:len(bomb)-(1<<14)+1])
+	if assert.NoError(t, err) {
+		assert.Equal(t, []HeaderField{field, field}, fields)
+	}
+
+	_, err = d.DecodeHeaderBlock([]byte{0xbe, 0xbe, 0xbe})
+	assert.Equal(t, compressionError("hpack: header list too large"), err)
+}
+
+func TestDecoderSetHeaderTableSize(t *testing.T) {
+	d := NewDecoder()
+	d.SetHeaderTableSize(settings.HeaderTableSize{Size: 8192})
+
+	_, err := d.DecodeHeaderBlock(unhex("3fe1 3f"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, uint32(8192), d.table.maxSize)
+	}
+
+	d.SetHeaderTableSize(settings.HeaderTableSize{Size: 0})
+
+	_, err = d.DecodeHeaderBlock(unhex("21"))
+	assert.Equal(t, compressionError("hpack: table size update too large"), err)
+}
+
+func TestDecoderSetHeaderTableSizeReduced(t *testing.T) {
+	e, d := NewEncoder(), NewDecoder()
+
+	_, err := d.DecodeHeaderBlock(e.AppendHeaderBlock(nil, []HeaderField{{Name: "x-custom", Value: "value"}}))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	d.SetHeaderTableSize(settings.HeaderTableSize{Size: 0})
+	assert.Equal(t, uint32(0), d.table.size)
+
+	_, err = d.DecodeHeaderBlock(unhex("82"))
+	assert.Equal(t, compressionError("hpack: missing table size update"), err)
+
+	d = NewDecoder()
+	d.SetHeaderTableSize(settings.HeaderTableSize{Size: 0})
+
+	fields, err := d.DecodeHeaderBlock(unhex("20 82"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []HeaderField{{Name: ":method", Value: "GET"}}, fields)
+	}
+
+	_, err = d.DecodeHeaderBlock(unhex("82"))
+	assert.NoError(t, err)
+}
+
+func TestRoundTripSensitive(t *testing.T) {
+	e, d := NewEncoder(), NewDecoder()
+
+	fields, err := d.DecodeHeaderBlock(e.AppendHeaderBlock(nil, []HeaderField{
+		{Name: "authorization", Value: "secret"},
+		{Name: "x-api-key", Value: "secret", Sensitive: true},
+		{Name: "accept", Value: "*/*"},
+	}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []HeaderField{
+			{Name: "authorization", Value: "secret", Sensitive: true},
+			{Name: "x-api-key", Value: "secret", Sensitive: true},
+			{Name: "accept", Value: "*/*"},
+		}, fields)
+	}
+
+	// NOTE(jc): a proxy re-encoding decoded fields MUST preserve their
+	// sensitivity, even without a policy of its own.
+	proxy := NewEncoder()
+	proxy.Sensitive = nil
+
+	b := proxy.AppendHeaderBlock(nil, fields)
+
+	result, err := NewDecoder().DecodeHeaderBlock(b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, fields, result)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	e, d := NewEncoder(), NewDecoder()
+
+	fields := []HeaderField{
+		{Name: ":method", Value: "POST"},
+		{Name: ":path", Value: "/upload"},
+		{Name: "content-type", Value: "application/octet-stream"},
+		{Name: "x-large", Value: strings.Repeat("a", DefaultHeaderTableSize)},
+	}
+
+	for i := 0; i < 3; i++ {
+		result, err := d.DecodeHeaderBlock(e.AppendHeaderBlock(nil, fields))
+		if assert.NoError(t, err) {
+			assert.Equal(t, fields, result)
+			assert.Equal(t, e.table.fields, d.table.fields)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package hpack
+
+import (
+	"sync"
+)
+
+// huffmanEOS is the symbol used to pad Huffman encoded strings, it MUST NOT
+// be decoded as part of a string.
+// RFC 7541 Section 5.2
+const huffmanEOS = 256
+
+// huffmanNode is a node in the lookup tree used to decode Huffman encoded
+// strings 8 bits at a time. Internal nodes have children indexed by the next
+// 8 bits of input, leaf nodes contain a symbol and the number of bits of the
+// final 8 used by its code.
+type huffmanNode struct {
+	children *[256]*huffmanNode
+	sym      uint16
+	length   uint8
+}
+
+var (
+	huffmanOnce sync.Once
+	huffmanRoot *huffmanNode
+)
+
+// huffmanTree returns the root of the lookup tree, building it on first use.
+func huffmanTree() *huffmanNode {
+	huffmanOnce.Do(func() {
+		huffmanRoot = &huffmanNode{children: new([256]*huffmanNode)}
+
+		for sym, c := range huffmanCodes {
+			addHuffmanCode(huffmanRoot, uint16(sym), c.code, c.length)
+		}
+	})
+
+	return huffmanRoot
+}
+
+// addHuffmanCode inserts sym into the lookup tree below n. A code that does
+// not end on an 8 bit boundary fills every child prefixed by its final bits.
+func addHuffmanCode(n *huffmanNode, sym uint16, code uint32, length uint8) {
+	for length > 8 {
+		length -= 8
+
+		i := uint8(code >> length)
+		if n.children[i] == nil {
+			n.children[i] = &huffmanNode{children: new([256]*huffmanNode)}
+		}
+
+		n = n.children[i]
+	}
+
+	leaf := &huffmanNode{sym: sym, length: length}
+
+	shift := 8 - length
+	start := int(uint8(code << shift))
+
+	for i := start; i < start+1<<shift; i++ {
+		n.children[i] = leaf
+	}
+}
+
+// huffmanLen returns the length of s once Huffman encoded, in bytes.
+func huffmanLen(s string) int {
+	var bits int
+
+	for i := 0; i < len(s); i++ {
+		bits += int(huffmanCodes[s[i]].length)
+	}
+
+	return (bits + 7) / 8
+}
+
+// appendHuffman appends s Huffman encoded to b, padded to a whole byte with
+// the most significant bits of EOS.
+// RFC 7541 Section 5.2
+func appendHuffman(b []byte, s string) []byte {
+	var (
+		cur  uint64
+		bits uint8
+	)
+
+	for i := 0; i < len(s); i++ {
+		c := huffmanCodes[s[i]]
+
+		cur = cur<<c.length | uint64(c.code)
+		bits += c.length
+
+		for bits >= 8 {
+			bits -= 8
+			b = append(b, byte(cur>>bits))
+		}
+	}
+
+	if bits > 0 {
+		pad := 8 - bits
+		b = append(b, byte(cur<<pad)|byte(1<<pad-1))
+	}
+
+	return b
+}
+
+// appendHuffmanDecode appends the Huffman encoded string s decoded to b. It
+// returns an error if s contains EOS, or is padded by more than 7 bits or by
+// anything other than the most significant bits of EOS.
+// RFC 7541 Section 5.2
+func appendHuffmanDecode(b, s []byte) ([]byte, error) {
+	root := huffmanTree()
+	n := root
+
+	var (
+		cur uint64
+
+		// bits is the number of bits of cur not yet consumed, and pending is
+		// the number of bits consumed by the symbol currently being decoded.
+		bits, pending uint8
+	)
+
+	for _, c := range s {
+		cur = cur<<8 | uint64(c)
+		bits += 8
+		pending += 8
+
+		for bits >= 8 {
+			n = n.children[byte(cur>>(bits-8))]
+			if n == nil {
+				return nil, compressionError("hpack: invalid huffman code")
+			} else if n.children != nil {
+				bits -= 8
+				continue
+			} else if n.sym == huffmanEOS {
+				return nil, compressionError("hpack: huffman encoded EOS")
+			}
+
+			b = append(b, byte(n.sym))
+			bits -= n.length
+			pending = bits
+			n = root
+		}
+	}
+
+	// NOTE(jc): fewer than 8 bits remain, which may contain symbols with
+	// short codes followed by padding.
+	for bits > 0 {
+		leaf := n.children[byte(cur<<(8-bits))]
+		if leaf == nil || leaf.children != nil || leaf.length > bits {
+			break
+		} else if leaf.sym == huffmanEOS {
+			return nil, compressionError("hpack: huffman encoded EOS")
+		}
+
+		b = append(b, byte(leaf.sym))
+		bits -= leaf.length
+		pending = bits
+		n = root
+	}
+
+	if pending > 7 {
+		return nil, compressionError("hpack: huffman padding too long")
+	} else if mask := uint64(1)<<bits - 1; cur&mask != mask {
+		return nil, compressionError("hpack: invalid huffman padding")
+	}
+
+	return b, nil
+}
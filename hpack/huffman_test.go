@@ -0,0 +1,93 @@
+package hpack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendHuffman(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Value string
+		Bytes []byte
+	}{
+		{"Empty", "", nil},
+		{"Authority", "www.example.com", unhex("f1e3 c2e5 f23a 6ba0 ab90 f4ff")},
+		{"CacheControl", "no-cache", unhex("a8eb 1064 9cbf")},
+		{"Date", "Mon, 21 Oct 2013 20:13:21 GMT", unhex("d07a be94 1054 d444 a820 0595 040b 8166 e082 a62d 1bff")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			b := appendHuffman(nil, test.Value)
+
+			assert.Equal(t, test.Bytes, b)
+			assert.Equal(t, len(test.Bytes), huffmanLen(test.Value))
+		})
+	}
+}
+
+func TestAppendHuffmanDecode(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Bytes []byte
+		Value string
+		Error error
+	}{
+		{"Empty", nil, "", nil},
+		{"Authority", unhex("f1e3 c2e5 f23a 6ba0 ab90 f4ff"), "www.example.com", nil},
+		{"CacheControl", unhex("a8eb 1064 9cbf"), "no-cache", nil},
+		{"LongCode", unhex("ffff ea"), "\t", nil},
+		{"EOS", unhex("ffff ffff"), "", compressionError("hpack: huffman encoded EOS")},
+		{"PaddingTooLong", unhex("1fff"), "", compressionError("hpack: huffman padding too long")},
+		{"PaddingFullByte", unhex("ff"), "", compressionError("hpack: huffman padding too long")},
+		{"PaddingNotEOS", unhex("18"), "", compressionError("hpack: invalid huffman padding")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			b, err := appendHuffmanDecode(nil, test.Bytes)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Value, string(b))
+				}
+			} else {
+				assert.Equal(t, test.Error, err)
+			}
+		})
+	}
+}
+
+func TestHuffmanRoundTrip(t *testing.T) {
+	var b strings.Builder
+
+	for i := 0; i < 256; i++ {
+		b.WriteByte(byte(i))
+	}
+
+	s := b.String()
+
+	for i := 0; i < len(s); i++ {
+		result, err := appendHuffmanDecode(nil, appendHuffman(nil, s[i:]))
+		if assert.NoError(t, err) {
+			assert.Equal(t, s[i:], string(result))
+		}
+	}
+}
+
+func BenchmarkAppendHuffmanDecode(b *testing.B) {
+	src := appendHuffman(nil, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko)")
+	dst := make([]byte, 0, 128)
+
+	b.SetBytes(int64(len(src)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := appendHuffmanDecode(dst[:0], src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package hpack
+
+// SensitivePolicy reports whether a HeaderField is sensitive, such as a
+// credential, and so MUST NOT enter the dynamic table where it may be
+// recovered by compression-based attacks.
+// RFC 7541 Section 7.1
+type SensitivePolicy func(f HeaderField) bool
+
+// DefaultSensitivePolicy treats the authorization, proxy-authorization and
+// cookie header fields as sensitive.
+var DefaultSensitivePolicy = SensitiveNames("authorization", "proxy-authorization", "cookie")
+
+// SensitiveNames returns a SensitivePolicy that treats any HeaderField with
+// one of names as sensitive. Names must be lowercase, as in HTTP/2.
+func SensitiveNames(names ...string) SensitivePolicy {
+	set := make(map[string]struct{}, len(names))
+
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+
+	return func(f HeaderField) bool {
+		_, ok := set[f.Name]
+		return ok
+	}
+}
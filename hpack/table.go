@@ -0,0 +1,194 @@
+package hpack
+
+// staticTable is the predefined, unchangeable list of header fields, indexed
+// from One (1).
+// RFC 7541 Appendix A
+var staticTable = [...]HeaderField{
+	{Name: ":authority"},
+	{Name: ":method", Value: "GET"},
+	{Name: ":method", Value: "POST"},
+	{Name: ":path", Value: "/"},
+	{Name: ":path", Value: "/index.html"},
+	{Name: ":scheme", Value: "http"},
+	{Name: ":scheme", Value: "https"},
+	{Name: ":status", Value: "200"},
+	{Name: ":status", Value: "204"},
+	{Name: ":status", Value: "206"},
+	{Name: ":status", Value: "304"},
+	{Name: ":status", Value: "400"},
+	{Name: ":status", Value: "404"},
+	{Name: ":status", Value: "500"},
+	{Name: "accept-charset"},
+	{Name: "accept-encoding", Value: "gzip, deflate"},
+	{Name: "accept-language"},
+	{Name: "accept-ranges"},
+	{Name: "accept"},
+	{Name: "access-control-allow-origin"},
+	{Name: "age"},
+	{Name: "allow"},
+	{Name: "authorization"},
+	{Name: "cache-control"},
+	{Name: "content-disposition"},
+	{Name: "content-encoding"},
+	{Name: "content-language"},
+	{Name: "content-length"},
+	{Name: "content-location"},
+	{Name: "content-range"},
+	{Name: "content-type"},
+	{Name: "cookie"},
+	{Name: "date"},
+	{Name: "etag"},
+	{Name: "expect"},
+	{Name: "expires"},
+	{Name: "from"},
+	{Name: "host"},
+	{Name: "if-match"},
+	{Name: "if-modified-since"},
+	{Name: "if-none-match"},
+	{Name: "if-range"},
+	{Name: "if-unmodified-since"},
+	{Name: "last-modified"},
+	{Name: "link"},
+	{Name: "location"},
+	{Name: "max-forwards"},
+	{Name: "proxy-authenticate"},
+	{Name: "proxy-authorization"},
+	{Name: "range"},
+	{Name: "referer"},
+	{Name: "refresh"},
+	{Name: "retry-after"},
+	{Name: "server"},
+	{Name: "set-cookie"},
+	{Name: "strict-transport-security"},
+	{Name: "transfer-encoding"},
+	{Name: "user-agent"},
+	{Name: "vary"},
+	{Name: "via"},
+	{Name: "www-authenticate"},
+}
+
+var (
+	// staticFields maps each name and value in staticTable to its index.
+	staticFields = make(map[HeaderField]uint64, len(staticTable))
+
+	// staticNames maps each name in staticTable to its lowest index.
+	staticNames = make(map[string]uint64, len(staticTable))
+)
+
+func init() {
+	for i, f := range staticTable {
+		staticFields[f] = uint64(i + 1)
+
+		if _, ok := staticNames[f.Name]; !ok {
+			staticNames[f.Name] = uint64(i + 1)
+		}
+	}
+}
+
+// dynamicTable is the list of header fields maintained by both an Encoder
+// and Decoder, evicting the oldest fields when its size would exceed its
+// maximum size.
+// RFC 7541 Section 2.3.2
+type dynamicTable struct {
+	// fields are ordered from oldest to newest.
+	fields  []HeaderField
+	size    uint32
+	maxSize uint32
+}
+
+// len returns the number of fields in the table.
+func (t *dynamicTable) len() int {
+	return len(t.fields)
+}
+
+// add inserts f as the newest field, first evicting as many fields as
+// required for it to fit. A field larger than the maximum size empties the
+// table and is not inserted.
+// RFC 7541 Section 4.4
+func (t *dynamicTable) add(f HeaderField) {
+	size := f.Size()
+
+	if size > t.maxSize {
+		t.evict(0)
+		return
+	}
+
+	t.evict(t.maxSize - size)
+
+	t.fields = append(t.fields, f)
+	t.size += size
+}
+
+// setMaxSize changes the maximum size of the table, evicting fields until
+// it fits.
+// RFC 7541 Section 4.3
+func (t *dynamicTable) setMaxSize(n uint32) {
+	t.maxSize = n
+	t.evict(n)
+}
+
+// evict removes the oldest fields until the size of the table is no larger
+// than n.
+func (t *dynamicTable) evict(n uint32) {
+	var i int
+
+	for t.size > n {
+		t.size -= t.fields[i].Size()
+		i++
+	}
+
+	if i > 0 {
+		t.fields = t.fields[:copy(t.fields, t.fields[i:])]
+	}
+}
+
+// field returns the field at index i of the combined static and dynamic
+// index address space, where the dynamic table begins immediately after the
+// static table with its newest field.
+// RFC 7541 Section 2.3.3
+func (t *dynamicTable) field(i uint64) (HeaderField, bool) {
+	if i < 1 {
+		return HeaderField{}, false
+	} else if i <= uint64(len(staticTable)) {
+		return staticTable[i-1], true
+	}
+
+	i -= uint64(len(staticTable))
+	if i > uint64(len(t.fields)) {
+		return HeaderField{}, false
+	}
+
+	return t.fields[len(t.fields)-int(i)], true
+}
+
+// search returns the index of f in the combined static and dynamic index
+// address space, true is returned if both its name and value matched,
+// otherwise only its name matched. An index of Zero (0) is returned if
+// neither matched.
+func (t *dynamicTable) search(f HeaderField) (uint64, bool) {
+	if i, ok := staticFields[HeaderField{Name: f.Name, Value: f.Value}]; ok {
+		return i, true
+	}
+
+	var name uint64
+
+	for i := len(t.fields) - 1; i >= 0; i-- {
+		if t.fields[i].Name != f.Name {
+			continue
+		}
+
+		index := uint64(len(staticTable) + len(t.fields) - i)
+
+		if t.fields[i].Value == f.Value {
+			return index, true
+		} else if name == 0 {
+			name = index
+		}
+	}
+
+	if i, ok := staticNames[f.Name]; ok {
+		return i, false
+	}
+
+	return name, false
+}
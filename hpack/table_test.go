@@ -0,0 +1,54 @@
+package hpack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicTable(t *testing.T) {
+	table := dynamicTable{maxSize: 100}
+
+	table.add(HeaderField{Name: "a", Value: "1"})
+	table.add(HeaderField{Name: "b", Value: "2"})
+	table.add(HeaderField{Name: "c", Value: "3"})
+
+	assert.Equal(t, 2, table.len())
+	assert.Equal(t, uint32(68), table.size)
+
+	f, ok := table.field(62)
+	if assert.True(t, ok) {
+		assert.Equal(t, HeaderField{Name: "c", Value: "3"}, f)
+	}
+
+	f, ok = table.field(63)
+	if assert.True(t, ok) {
+		assert.Equal(t, HeaderField{Name: "b", Value: "2"}, f)
+	}
+
+	_, ok = table.field(64)
+	assert.False(t, ok)
+
+	i, exact := table.search(HeaderField{Name: "b", Value: "2"})
+	assert.Equal(t, uint64(63), i)
+	assert.True(t, exact)
+
+	i, exact = table.search(HeaderField{Name: "c", Value: "4"})
+	assert.Equal(t, uint64(62), i)
+	assert.False(t, exact)
+
+	i, exact = table.search(HeaderField{Name: "age", Value: "60"})
+	assert.Equal(t, uint64(21), i)
+	assert.False(t, exact)
+
+	i, _ = table.search(HeaderField{Name: "d", Value: "4"})
+	assert.Equal(t, uint64(0), i)
+
+	table.setMaxSize(40)
+	assert.Equal(t, 1, table.len())
+	assert.Equal(t, uint32(34), table.size)
+
+	table.add(HeaderField{Name: "too-large", Value: "value"})
+	assert.Equal(t, 0, table.len())
+	assert.Equal(t, uint32(0), table.size)
+}
@@ -0,0 +1,61 @@
+package settings
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// HeaderName is the name of the HTTP/1.1 header field used by a client to
+// send its Settings when upgrading to HTTP/2 without TLS (h2c).
+// RFC 7540 Section 3.2.1
+const HeaderName = "HTTP2-Settings"
+
+// ErrHeader is returned when decoding an HTTP2-Settings header field value
+// that is not base64url encoded, or does not contain a whole number of
+// Settings. Servers MUST NOT upgrade the connection in this case.
+var ErrHeader = errors.New("settings: invalid HTTP2-Settings header")
+
+// EncodeHeader marshals settings to the payload of a Settings frame and
+// returns it base64url encoded, without padding, for use as the value of an
+// HTTP2-Settings header field.
+// RFC 7540 Section 3.2.1
+func EncodeHeader(settings []Setting) string {
+	b := make([]byte, 0, len(settings)*6)
+
+	for _, setting := range settings {
+		b = AppendSetting(b, setting)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeHeader unmarshals the value of an HTTP2-Settings header field. Each
+// Setting is validated, and Settings with an unknown identifier are returned
+// as Unknown.
+// RFC 7540 Section 3.2.1
+func DecodeHeader(value string) ([]Setting, error) {
+	// NOTE(jc): padding is not permitted by the grammar, but is commonly
+	// sent by clients and is trivially ignored.
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil || len(b)%6 != 0 {
+		return nil, ErrHeader
+	}
+
+	settings := make([]Setting, 0, len(b)/6)
+
+	for ; len(b) > 0; b = b[6:] {
+		// NOTE(jc): unknown settings are returned by ParseSetting as Unknown,
+		// which are never invalid.
+		setting, err := ParseSetting(b)
+		if err != nil && err != ErrUnknown {
+			return nil, err
+		} else if err := setting.Validate(); err != nil {
+			return nil, err
+		}
+
+		settings = append(settings, setting)
+	}
+
+	return settings, nil
+}
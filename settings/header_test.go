@@ -0,0 +1,79 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeHeader(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Settings []Setting
+		Value    string
+	}{
+		{"Nil", nil, ""},
+		{
+			"curl",
+			[]Setting{
+				MaxConcurrentStreams{Streams: 100},
+				InitialWindowSize{Size: 33554432},
+				EnablePush{Enabled: false},
+			},
+			"AAMAAABkAAQCAAAAAAIAAAAA",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			value := EncodeHeader(test.Settings)
+
+			assert.Equal(t, test.Value, value)
+		})
+	}
+}
+
+func TestDecodeHeader(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Value    string
+		Settings []Setting
+		Error    error
+	}{
+		{"Empty", "", []Setting{}, nil},
+		{
+			"curl",
+			"AAMAAABkAAQCAAAAAAIAAAAA",
+			[]Setting{
+				MaxConcurrentStreams{Streams: 100},
+				InitialWindowSize{Size: 33554432},
+				EnablePush{Enabled: false},
+			},
+			nil,
+		},
+		{"Padding", "AAIAAAAB==", []Setting{EnablePush{Enabled: true}}, nil},
+		{"Unknown", "CgoSNFZ4", []Setting{Unknown{Identifier: 0x0A0A, Raw: 0x12345678}}, nil},
+		{"Base64", "AAIAAAAA+/", nil, ErrHeader},
+		{"Length", "AAIAAA", nil, ErrHeader},
+		{"EnablePush", "AAIAAAAC", nil, ErrBoolean},
+		{"InitialWindowSize", "AASAAAAA", nil, ErrInitialWindowSize},
+		{"MaxFrameSize", "AAUAAD__", nil, ErrMaxFrameSize},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			settings, err := DecodeHeader(test.Value)
+
+			if test.Error == nil {
+				if assert.NoError(t, err) {
+					assert.Equal(t, test.Settings, settings)
+				}
+			} else {
+				if assert.Error(t, err) {
+					assert.Nil(t, settings)
+					assert.Equal(t, test.Error, err)
+				}
+			}
+		})
+	}
+}
@@ -9,9 +9,25 @@ var (
 	// been supplied.
 	ErrInvalid = errors.New("settings: short")
 
-	// ErrUnknown is returned when parsing a Setting but its identifier is
-	// not supported by this package. Receivers MUST ignore unknown settings.
+	// ErrUnknown is returned along with an Unknown Setting when parsing a
+	// Setting but its identifier is not supported by this package.
+	// Receivers MUST ignore unknown settings.
 	ErrUnknown = errors.New("settings: unknown identifier")
+
+	// ErrBoolean is returned when parsing a Setting that may only be Zero (0)
+	// or One (1) but another value was given. Receivers MUST treat this as a
+	// connection error of type PROTOCOL_ERROR.
+	ErrBoolean = errors.New("settings: value must be 0 or 1")
+
+	// ErrInitialWindowSize is returned when validating an InitialWindowSize
+	// above MaxInitialWindowSize. Receivers MUST treat this as a connection
+	// error of type FLOW_CONTROL_ERROR.
+	ErrInitialWindowSize = errors.New("settings: initial window size too large")
+
+	// ErrMaxFrameSize is returned when validating a MaxFrameSize outside of
+	// MinMaxFrameSize and MaxMaxFrameSize. Receivers MUST treat this as a
+	// connection error of type PROTOCOL_ERROR.
+	ErrMaxFrameSize = errors.New("settings: max frame size out of range")
 )
 
 // AppendSetting marshals a Setting to the wire format, appends it to b and
@@ -35,8 +51,11 @@ func AppendSetting(b []byte, setting Setting) []byte {
 }
 
 // ParseSetting unmarshals a Setting from the wire format. ErrUnknown is
-// returned if a Setting with an unknown identifier is encountered, receivers
-// MUST ignore unknown settings.
+// returned along with an Unknown Setting if a Setting with an identifier
+// neither understood by this package nor registered with RegisterSetting is
+// encountered, receivers MUST ignore unknown settings. ErrBoolean is returned
+// if a Setting that may only be enabled or disabled has any other value. The
+// range of other values is not checked, see Setting.Validate.
 // RFC 7540 Section 6.5.1
 func ParseSetting(b []byte) (Setting, error) {
 	if len(b) < 6 {
@@ -51,7 +70,11 @@ func ParseSetting(b []byte) (Setting, error) {
 		return HeaderTableSize{Size: v}, nil
 
 	case EnablePushID:
-		return EnablePush{Enabled: v > 0}, nil
+		if v > 1 {
+			return nil, ErrBoolean
+		}
+
+		return EnablePush{Enabled: v == 1}, nil
 
 	case MaxConcurrentStreamsID:
 		return MaxConcurrentStreams{Streams: v}, nil
@@ -65,7 +88,25 @@ func ParseSetting(b []byte) (Setting, error) {
 	case MaxHeaderListSizeID:
 		return MaxHeaderListSize{Size: v}, nil
 
+	case EnableConnectProtocolID:
+		if v > 1 {
+			return nil, ErrBoolean
+		}
+
+		return EnableConnectProtocol{Enabled: v == 1}, nil
+
+	case NoRFC7540PrioritiesID:
+		if v > 1 {
+			return nil, ErrBoolean
+		}
+
+		return NoRFC7540Priorities{Enabled: v == 1}, nil
+
 	default:
-		return nil, ErrUnknown
+		if setting, ok := registeredSetting(k, v); ok {
+			return setting, nil
+		}
+
+		return Unknown{Identifier: k, Raw: v}, ErrUnknown
 	}
 }
@@ -20,6 +20,9 @@ func TestAppendSetting(t *testing.T) {
 		{"InitialWindowSize", nil, InitialWindowSize{Size: 65535}, []byte{0x00, 0x04, 0x00, 0x00, 0xFF, 0xFF}},
 		{"MaxFrameSize", nil, MaxFrameSize{Size: 16384}, []byte{0x00, 0x05, 0x00, 0x00, 0x40, 0x00}},
 		{"MaxHeaderListSize", nil, MaxHeaderListSize{Size: 65535}, []byte{0x00, 0x06, 0x00, 0x00, 0xFF, 0xFF}},
+		{"EnableConnectProtocol", nil, EnableConnectProtocol{Enabled: true}, []byte{0x00, 0x08, 0x00, 0x00, 0x00, 0x01}},
+		{"NoRFC7540Priorities", nil, NoRFC7540Priorities{Enabled: true}, []byte{0x00, 0x09, 0x00, 0x00, 0x00, 0x01}},
+		{"Unknown", nil, Unknown{Identifier: 0x0A0A, Raw: 0x12345678}, []byte{0x0A, 0x0A, 0x12, 0x34, 0x56, 0x78}},
 	}
 
 	for _, test := range tests {
@@ -46,7 +49,12 @@ func TestParseSetting(t *testing.T) {
 		{"InitialWindowSize", []byte{0x00, 0x04, 0x00, 0x00, 0xFF, 0xFF}, InitialWindowSize{Size: 65535}, nil},
 		{"MaxFrameSize", []byte{0x00, 0x05, 0x00, 0x00, 0x40, 0x00}, MaxFrameSize{Size: 16384}, nil},
 		{"MaxHeaderListSize", []byte{0x00, 0x06, 0x00, 0x00, 0xFF, 0xFF}, MaxHeaderListSize{Size: 65535}, nil},
-		{"Unknown", []byte{0xFF, 0xFF, 0x00, 0x00, 0x00, 0x01}, nil, ErrUnknown},
+		{"EnableConnectProtocol", []byte{0x00, 0x08, 0x00, 0x00, 0x00, 0x01}, EnableConnectProtocol{Enabled: true}, nil},
+		{"NoRFC7540Priorities", []byte{0x00, 0x09, 0x00, 0x00, 0x00, 0x01}, NoRFC7540Priorities{Enabled: true}, nil},
+		{"EnablePushInvalid", []byte{0x00, 0x02, 0x00, 0x00, 0x00, 0x02}, nil, ErrBoolean},
+		{"EnableConnectProtocolInvalid", []byte{0x00, 0x08, 0x00, 0x00, 0x00, 0x02}, nil, ErrBoolean},
+		{"NoRFC7540PrioritiesInvalid", []byte{0x00, 0x09, 0x00, 0x00, 0x00, 0x02}, nil, ErrBoolean},
+		{"Unknown", []byte{0xFF, 0xFF, 0x00, 0x00, 0x00, 0x01}, Unknown{Identifier: 0xFFFF, Raw: 0x1}, ErrUnknown},
 	}
 
 	for _, test := range tests {
@@ -59,7 +67,7 @@ func TestParseSetting(t *testing.T) {
 				}
 			} else {
 				if assert.Error(t, err) {
-					assert.Nil(t, setting)
+					assert.Equal(t, test.Setting, setting)
 					assert.Equal(t, test.Error, err)
 				}
 			}
@@ -0,0 +1,91 @@
+package settings
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[uint16]func(value uint32) Setting)
+)
+
+// RegisterSetting registers fn to construct Settings with identifier id,
+// which are then returned by ParseSetting instead of ErrUnknown. Settings
+// constructed by fn are validated by their Validate method when received in a
+// Settings frame, any error is treated as a connection error of type
+// PROTOCOL_ERROR. RegisterSetting panics if id is already understood by this
+// package or has already been registered.
+// RFC 7540 Section 5.5
+func RegisterSetting(id uint16, fn func(value uint32) Setting) {
+	if builtinSetting(id) {
+		panic(fmt.Sprintf("settings: cannot register builtin setting 0x%x", id))
+	} else if fn == nil {
+		panic("settings: nil setting constructor")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[id]; ok {
+		panic(fmt.Sprintf("settings: setting 0x%x already registered", id))
+	}
+
+	registry[id] = fn
+}
+
+// registeredSetting returns a new Setting with identifier id and value v if
+// one has been registered.
+func registeredSetting(id uint16, v uint32) (Setting, bool) {
+	registryMu.RLock()
+	fn, ok := registry[id]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	return fn(v), true
+}
+
+// builtinSetting returns true if id is understood by ParseSetting without
+// registration.
+func builtinSetting(id uint16) bool {
+	switch id {
+	case HeaderTableSizeID, EnablePushID, MaxConcurrentStreamsID,
+		InitialWindowSizeID, MaxFrameSizeID, MaxHeaderListSizeID,
+		EnableConnectProtocolID, NoRFC7540PrioritiesID:
+		return true
+	default:
+		return false
+	}
+}
+
+// Unknown is a Setting with an identifier not understood by this package or
+// registered with RegisterSetting, such as a GREASE or vendor setting. It is
+// preserved so it may be logged or forwarded, otherwise it MUST be ignored.
+// RFC 7540 Section 6.5.2
+type Unknown struct {
+	// Identifier is the identifier of the Setting, it is not named ID as that
+	// is used to implement Setting.
+	Identifier uint16
+
+	// Raw is the 4-byte (32-bit) value of the Setting.
+	Raw uint32
+}
+
+// ID implements Setting.
+func (u Unknown) ID() uint16 {
+	return u.Identifier
+}
+
+// Value implements Setting.
+func (u Unknown) Value() uint32 {
+	return u.Raw
+}
+
+// Validate implements Setting. The value of an Unknown setting is never
+// invalid.
+func (u Unknown) Validate() error {
+	return nil
+}
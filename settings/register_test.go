@@ -0,0 +1,66 @@
+package settings
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// experimentID is an extension setting identifier used only by tests.
+const experimentID = uint16(0xF000)
+
+var errExperiment = errors.New("experiment: value too large")
+
+type experiment struct {
+	Level uint32
+}
+
+func (e experiment) ID() uint16 {
+	return experimentID
+}
+
+func (e experiment) Value() uint32 {
+	return e.Level
+}
+
+func (e experiment) Validate() error {
+	if e.Level > 10 {
+		return errExperiment
+	}
+
+	return nil
+}
+
+func TestRegisterSetting(t *testing.T) {
+	RegisterSetting(experimentID, func(v uint32) Setting { return experiment{Level: v} })
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, experimentID)
+		registryMu.Unlock()
+	})
+
+	setting, err := ParseSetting([]byte{0xF0, 0x00, 0x00, 0x00, 0x00, 0x05})
+	if assert.NoError(t, err) {
+		assert.Equal(t, experiment{Level: 5}, setting)
+		assert.NoError(t, setting.Validate())
+	}
+
+	setting, err = ParseSetting([]byte{0xF0, 0x00, 0x00, 0x00, 0x00, 0x0B})
+	if assert.NoError(t, err) {
+		assert.Equal(t, errExperiment, setting.Validate())
+	}
+
+	_, err = ParseSetting([]byte{0xF0, 0x01, 0x00, 0x00, 0x00, 0x05})
+	assert.Equal(t, ErrUnknown, err)
+
+	assert.Panics(t, func() {
+		RegisterSetting(experimentID, func(v uint32) Setting { return experiment{Level: v} })
+	})
+	assert.Panics(t, func() {
+		RegisterSetting(EnablePushID, func(v uint32) Setting { return EnablePush{Enabled: v == 1} })
+	})
+	assert.Panics(t, func() {
+		RegisterSetting(experimentID, nil)
+	})
+}
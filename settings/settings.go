@@ -32,6 +32,31 @@ const (
 	// SETTINGS_MAX_HEADER_LIST_SIZE setting.
 	// RFC 7540 Section 6.5.2
 	MaxHeaderListSizeID = uint16(0x6)
+
+	// EnableConnectProtocolID (0x8) is the identifier for the
+	// SETTINGS_ENABLE_CONNECT_PROTOCOL setting.
+	// RFC 8441 Section 3
+	EnableConnectProtocolID = uint16(0x8)
+
+	// NoRFC7540PrioritiesID (0x9) is the identifier for the
+	// SETTINGS_NO_RFC7540_PRIORITIES setting.
+	// RFC 9218 Section 2.1
+	NoRFC7540PrioritiesID = uint16(0x9)
+)
+
+const (
+	// MaxInitialWindowSize is the largest value permitted for
+	// InitialWindowSize.
+	// RFC 7540 Section 6.5.2
+	MaxInitialWindowSize = uint32(1<<31 - 1)
+
+	// MinMaxFrameSize is the smallest value permitted for MaxFrameSize.
+	// RFC 7540 Section 6.5.2
+	MinMaxFrameSize = uint32(1 << 14)
+
+	// MaxMaxFrameSize is the largest value permitted for MaxFrameSize.
+	// RFC 7540 Section 6.5.2
+	MaxMaxFrameSize = uint32(1<<24 - 1)
 )
 
 // Setting is implemented by types that contain connection-level configuration
@@ -44,6 +69,10 @@ type Setting interface {
 
 	// Value is the 4-byte (32-bit) value of a Setting.
 	Value() uint32
+
+	// Validate returns an error if the value of a Setting is outside of its
+	// permitted range.
+	Validate() error
 }
 
 // HeaderTableSize informs a peer of the maximum size of the header compression
@@ -63,6 +92,11 @@ func (h HeaderTableSize) Value() uint32 {
 	return h.Size
 }
 
+// Validate implements Setting.
+func (h HeaderTableSize) Validate() error {
+	return nil
+}
+
 // EnablePush informs a peer if the sender can receive PushPromise Frames.
 // RFC 7540 Section 6.5.2
 type EnablePush struct {
@@ -83,6 +117,11 @@ func (e EnablePush) Value() uint32 {
 	return 0
 }
 
+// Validate implements Setting.
+func (e EnablePush) Validate() error {
+	return nil
+}
+
 // MaxConcurrentStreams limits the maximum number of concurrent active streams.
 // A value of Zero (0) is NOT special, and should only be used to reject new
 // streams.
@@ -101,6 +140,11 @@ func (m MaxConcurrentStreams) Value() uint32 {
 	return m.Streams
 }
 
+// Validate implements Setting.
+func (m MaxConcurrentStreams) Validate() error {
+	return nil
+}
+
 // InitialWindowSize indicates to a peer the initial window size of the sender,
 // which may be updated later with a WindowUpdate Frame, in bytes.
 // RFC 7540 Section 6.5.2
@@ -118,6 +162,15 @@ func (i InitialWindowSize) Value() uint32 {
 	return i.Size
 }
 
+// Validate implements Setting.
+func (i InitialWindowSize) Validate() error {
+	if i.Size > MaxInitialWindowSize {
+		return ErrInitialWindowSize
+	}
+
+	return nil
+}
+
 // MaxFrameSize indicates to a peer the maximum size of a payload to accept.
 // RFC 7540 Section 6.5.2
 type MaxFrameSize struct {
@@ -134,6 +187,15 @@ func (m MaxFrameSize) Value() uint32 {
 	return m.Size
 }
 
+// Validate implements Setting.
+func (m MaxFrameSize) Validate() error {
+	if m.Size < MinMaxFrameSize || m.Size > MaxMaxFrameSize {
+		return ErrMaxFrameSize
+	}
+
+	return nil
+}
+
 // MaxHeaderListSize advises the maximum size of the uncompressed header list
 // the sender will accept, in bytes.
 // RFC 7540 Section 6.5.2
@@ -150,3 +212,62 @@ func (m MaxHeaderListSize) ID() uint16 {
 func (m MaxHeaderListSize) Value() uint32 {
 	return m.Size
 }
+
+// Validate implements Setting.
+func (m MaxHeaderListSize) Validate() error {
+	return nil
+}
+
+// EnableConnectProtocol informs a peer that the sender supports the Extended
+// CONNECT method, allowing protocols such as WebSockets to be bootstrapped
+// over a Stream. Once enabled it MUST NOT be disabled.
+// RFC 8441 Section 3
+type EnableConnectProtocol struct {
+	Enabled bool
+}
+
+// ID implements Setting.
+func (e EnableConnectProtocol) ID() uint16 {
+	return EnableConnectProtocolID
+}
+
+// Value implements Setting.
+func (e EnableConnectProtocol) Value() uint32 {
+	if e.Enabled {
+		return 1
+	}
+
+	return 0
+}
+
+// Validate implements Setting.
+func (e EnableConnectProtocol) Validate() error {
+	return nil
+}
+
+// NoRFC7540Priorities informs a peer that the sender does not use the
+// deprecated RFC 7540 prioritization scheme, and instead uses the extensible
+// prioritization scheme of RFC 9218.
+// RFC 9218 Section 2.1
+type NoRFC7540Priorities struct {
+	Enabled bool
+}
+
+// ID implements Setting.
+func (n NoRFC7540Priorities) ID() uint16 {
+	return NoRFC7540PrioritiesID
+}
+
+// Value implements Setting.
+func (n NoRFC7540Priorities) Value() uint32 {
+	if n.Enabled {
+		return 1
+	}
+
+	return 0
+}
+
+// Validate implements Setting.
+func (n NoRFC7540Priorities) Validate() error {
+	return nil
+}
@@ -0,0 +1,36 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Setting Setting
+		Error   error
+	}{
+		{"HeaderTableSize", HeaderTableSize{Size: 1<<32 - 1}, nil},
+		{"EnablePush", EnablePush{Enabled: true}, nil},
+		{"MaxConcurrentStreams", MaxConcurrentStreams{Streams: 0}, nil},
+		{"InitialWindowSize", InitialWindowSize{Size: 1<<31 - 1}, nil},
+		{"InitialWindowSizeTooLarge", InitialWindowSize{Size: 1 << 31}, ErrInitialWindowSize},
+		{"MaxFrameSizeMin", MaxFrameSize{Size: 16384}, nil},
+		{"MaxFrameSizeMax", MaxFrameSize{Size: 16777215}, nil},
+		{"MaxFrameSizeTooSmall", MaxFrameSize{Size: 16383}, ErrMaxFrameSize},
+		{"MaxFrameSizeTooLarge", MaxFrameSize{Size: 16777216}, ErrMaxFrameSize},
+		{"MaxHeaderListSize", MaxHeaderListSize{Size: 0}, nil},
+		{"EnableConnectProtocol", EnableConnectProtocol{Enabled: true}, nil},
+		{"NoRFC7540Priorities", NoRFC7540Priorities{Enabled: true}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Setting.Validate()
+
+			assert.Equal(t, test.Error, err)
+		})
+	}
+}
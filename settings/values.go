@@ -0,0 +1,100 @@
+package settings
+
+// Unlimited is used by Values to represent a Setting with no initial limit,
+// such as MaxConcurrentStreams and MaxHeaderListSize.
+const Unlimited = uint32(1<<32 - 1)
+
+// Values is a snapshot of the effective configuration of a peer, built by
+// applying each Setting it sends over the lifetime of a connection.
+type Values struct {
+	HeaderTableSize       HeaderTableSize
+	EnablePush            EnablePush
+	MaxConcurrentStreams  MaxConcurrentStreams
+	InitialWindowSize     InitialWindowSize
+	MaxFrameSize          MaxFrameSize
+	MaxHeaderListSize     MaxHeaderListSize
+	EnableConnectProtocol EnableConnectProtocol
+	NoRFC7540Priorities   NoRFC7540Priorities
+}
+
+// DefaultValues returns the initial configuration of a peer before any
+// Setting has been received.
+// RFC 7540 Section 6.5.2
+func DefaultValues() Values {
+	return Values{
+		HeaderTableSize:      HeaderTableSize{Size: 4096},
+		EnablePush:           EnablePush{Enabled: true},
+		MaxConcurrentStreams: MaxConcurrentStreams{Streams: Unlimited},
+		InitialWindowSize:    InitialWindowSize{Size: 65535},
+		MaxFrameSize:         MaxFrameSize{Size: MinMaxFrameSize},
+		MaxHeaderListSize:    MaxHeaderListSize{Size: Unlimited},
+	}
+}
+
+// Apply updates v with each Setting in order, such that the last value of any
+// Setting given more than once wins. Settings unknown to Values are ignored.
+// Settings are not validated, see Setting.Validate.
+// RFC 7540 Section 6.5.3
+func (v *Values) Apply(settings []Setting) {
+	for _, setting := range settings {
+		switch setting := setting.(type) {
+		case HeaderTableSize:
+			v.HeaderTableSize = setting
+		case EnablePush:
+			v.EnablePush = setting
+		case MaxConcurrentStreams:
+			v.MaxConcurrentStreams = setting
+		case InitialWindowSize:
+			v.InitialWindowSize = setting
+		case MaxFrameSize:
+			v.MaxFrameSize = setting
+		case MaxHeaderListSize:
+			v.MaxHeaderListSize = setting
+		case EnableConnectProtocol:
+			v.EnableConnectProtocol = setting
+		case NoRFC7540Priorities:
+			v.NoRFC7540Priorities = setting
+		}
+	}
+}
+
+// Diff returns the minimal list of Settings that, when applied to v, result
+// in other, ordered by identifier. An empty list is returned if v and other
+// are equal.
+func (v Values) Diff(other Values) []Setting {
+	var settings []Setting
+
+	if v.HeaderTableSize != other.HeaderTableSize {
+		settings = append(settings, other.HeaderTableSize)
+	}
+
+	if v.EnablePush != other.EnablePush {
+		settings = append(settings, other.EnablePush)
+	}
+
+	if v.MaxConcurrentStreams != other.MaxConcurrentStreams {
+		settings = append(settings, other.MaxConcurrentStreams)
+	}
+
+	if v.InitialWindowSize != other.InitialWindowSize {
+		settings = append(settings, other.InitialWindowSize)
+	}
+
+	if v.MaxFrameSize != other.MaxFrameSize {
+		settings = append(settings, other.MaxFrameSize)
+	}
+
+	if v.MaxHeaderListSize != other.MaxHeaderListSize {
+		settings = append(settings, other.MaxHeaderListSize)
+	}
+
+	if v.EnableConnectProtocol != other.EnableConnectProtocol {
+		settings = append(settings, other.EnableConnectProtocol)
+	}
+
+	if v.NoRFC7540Priorities != other.NoRFC7540Priorities {
+		settings = append(settings, other.NoRFC7540Priorities)
+	}
+
+	return settings
+}
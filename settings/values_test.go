@@ -0,0 +1,140 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultValues(t *testing.T) {
+	v := DefaultValues()
+
+	assert.Equal(t, uint32(4096), v.HeaderTableSize.Size)
+	assert.True(t, v.EnablePush.Enabled)
+	assert.Equal(t, Unlimited, v.MaxConcurrentStreams.Streams)
+	assert.Equal(t, uint32(65535), v.InitialWindowSize.Size)
+	assert.Equal(t, uint32(16384), v.MaxFrameSize.Size)
+	assert.Equal(t, Unlimited, v.MaxHeaderListSize.Size)
+	assert.False(t, v.EnableConnectProtocol.Enabled)
+	assert.False(t, v.NoRFC7540Priorities.Enabled)
+}
+
+func TestValuesApply(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Settings []Setting
+		Values   func(v *Values)
+	}{
+		{"Nil", nil, func(v *Values) {}},
+		{
+			"nghttp2.org",
+			[]Setting{
+				MaxConcurrentStreams{Streams: 100},
+				InitialWindowSize{Size: 1073741824},
+				EnablePush{Enabled: false},
+			},
+			func(v *Values) {
+				v.MaxConcurrentStreams.Streams = 100
+				v.InitialWindowSize.Size = 1073741824
+				v.EnablePush.Enabled = false
+			},
+		},
+		{
+			"LastWins",
+			[]Setting{
+				MaxFrameSize{Size: 32768},
+				HeaderTableSize{Size: 0},
+				MaxFrameSize{Size: 65536},
+			},
+			func(v *Values) {
+				v.HeaderTableSize.Size = 0
+				v.MaxFrameSize.Size = 65536
+			},
+		},
+		{
+			"Extensions",
+			[]Setting{
+				MaxHeaderListSize{Size: 8192},
+				EnableConnectProtocol{Enabled: true},
+				NoRFC7540Priorities{Enabled: true},
+			},
+			func(v *Values) {
+				v.MaxHeaderListSize.Size = 8192
+				v.EnableConnectProtocol.Enabled = true
+				v.NoRFC7540Priorities.Enabled = true
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			expected := DefaultValues()
+			test.Values(&expected)
+
+			v := DefaultValues()
+			v.Apply(test.Settings)
+
+			assert.Equal(t, expected, v)
+		})
+	}
+}
+
+func TestValuesDiff(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Values   func(v *Values)
+		Settings []Setting
+	}{
+		{"Equal", func(v *Values) {}, nil},
+		{
+			"Changed",
+			func(v *Values) {
+				v.EnablePush.Enabled = false
+				v.MaxConcurrentStreams.Streams = 100
+				v.InitialWindowSize.Size = 1073741824
+			},
+			[]Setting{
+				EnablePush{Enabled: false},
+				MaxConcurrentStreams{Streams: 100},
+				InitialWindowSize{Size: 1073741824},
+			},
+		},
+		{
+			"All",
+			func(v *Values) {
+				v.HeaderTableSize.Size = 0
+				v.EnablePush.Enabled = false
+				v.MaxConcurrentStreams.Streams = 0
+				v.InitialWindowSize.Size = 0
+				v.MaxFrameSize.Size = 16777215
+				v.MaxHeaderListSize.Size = 0
+				v.EnableConnectProtocol.Enabled = true
+				v.NoRFC7540Priorities.Enabled = true
+			},
+			[]Setting{
+				HeaderTableSize{Size: 0},
+				EnablePush{Enabled: false},
+				MaxConcurrentStreams{Streams: 0},
+				InitialWindowSize{Size: 0},
+				MaxFrameSize{Size: 16777215},
+				MaxHeaderListSize{Size: 0},
+				EnableConnectProtocol{Enabled: true},
+				NoRFC7540Priorities{Enabled: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			other := DefaultValues()
+			test.Values(&other)
+
+			settings := DefaultValues().Diff(other)
+			assert.Equal(t, test.Settings, settings)
+
+			v := DefaultValues()
+			v.Apply(settings)
+			assert.Equal(t, other, v)
+		})
+	}
+}